@@ -0,0 +1,271 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+	"sigs.k8s.io/yaml"
+)
+
+// backendsTarget turns the -backends flag into a dial target: a full target such as dns:///host:port
+// is used as is, @file is a watched backends file and anything else a comma separated static list.
+func backendsTarget(backends string) (string, error) {
+	if strings.Contains(backends, "://") {
+		return backends, nil
+	}
+	if path, ok := strings.CutPrefix(backends, "@"); ok {
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return "", err
+		}
+		return fileScheme + "://" + filepath.ToSlash(abs), nil
+	}
+	if len(splitBackends(backends)) == 0 {
+		return "", fmt.Errorf("no backend in %q", backends)
+	}
+	return staticScheme + ":///" + backends, nil
+}
+
+const (
+	staticScheme = "static"
+	fileScheme   = "file"
+)
+
+func splitBackends(s string) []string {
+	var addrs []string
+	for _, addr := range strings.Split(s, ",") {
+		if addr = strings.TrimSpace(addr); addr != "" {
+			addrs = append(addrs, addr)
+		}
+	}
+	return addrs
+}
+
+// backendsState gives every address an endpoint of its own, for the balancers that only read endpoints
+func backendsState(addrs []string) resolver.State {
+	state := resolver.State{}
+	for _, addr := range addrs {
+		a := resolver.Address{Addr: addr}
+		state.Addresses = append(state.Addresses, a)
+		state.Endpoints = append(state.Endpoints, resolver.Endpoint{Addresses: []resolver.Address{a}})
+	}
+	return state
+}
+
+// staticResolverBuilder resolves static:///host1:port1,host2:port2 once
+type staticResolverBuilder struct{}
+
+func (staticResolverBuilder) Scheme() string { return staticScheme }
+
+func (staticResolverBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	addrs := splitBackends(target.Endpoint())
+	if len(addrs) == 0 {
+		return nil, fmt.Errorf("no backend in %q", target.Endpoint())
+	}
+	if err := cc.UpdateState(backendsState(addrs)); err != nil {
+		return nil, err
+	}
+	return staticResolver{}, nil
+}
+
+type staticResolver struct{}
+
+func (staticResolver) ResolveNow(resolver.ResolveNowOptions) {}
+
+func (staticResolver) Close() {}
+
+// backendsFile is the YAML or JSON file of a file:///path target
+type backendsFile struct {
+	Backends []string `json:"backends"`
+}
+
+// fileResolverBuilder resolves file:///path, the file is polled and every change is resolved again live
+type fileResolverBuilder struct{}
+
+func (fileResolverBuilder) Scheme() string { return fileScheme }
+
+func (fileResolverBuilder) Build(target resolver.Target, cc resolver.ClientConn, _ resolver.BuildOptions) (resolver.Resolver, error) {
+	r := &fileResolver{
+		path:       filepath.FromSlash(target.URL.Path),
+		cc:         cc,
+		resolveNow: make(chan struct{}, 1),
+		done:       make(chan struct{}),
+	}
+	if err := r.resolve(); err != nil {
+		return nil, err
+	}
+	go r.watch()
+	return r, nil
+}
+
+// fileResolverInterval is how often the backends file is checked for changes
+const fileResolverInterval = time.Second
+
+type fileResolver struct {
+	path       string
+	cc         resolver.ClientConn
+	resolveNow chan struct{}
+	done       chan struct{}
+
+	// only touched by resolve, which runs in Build and then in watch
+	modTime time.Time
+	addrs   []string
+}
+
+func (r *fileResolver) watch() {
+	ticker := time.NewTicker(fileResolverInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.done:
+			return
+		case <-ticker.C:
+		case <-r.resolveNow:
+			// force a read, e.g. the file was fixed without changing its mtime
+			r.modTime = time.Time{}
+		}
+		if err := r.resolve(); err != nil {
+			// grpc keeps the last good backends and calls ResolveNow again with a backoff
+			r.cc.ReportError(err)
+		}
+	}
+}
+
+// resolve reads the file when it changed and updates the backends when they changed
+func (r *fileResolver) resolve() error {
+	info, err := os.Stat(r.path)
+	if err != nil {
+		return err
+	}
+	if info.ModTime().Equal(r.modTime) {
+		return nil
+	}
+	data, err := os.ReadFile(r.path)
+	if err != nil {
+		return err
+	}
+	f := backendsFile{}
+	if err := yaml.UnmarshalStrict(data, &f); err != nil {
+		return fmt.Errorf("invalid backends file %s: %v", r.path, err)
+	}
+	addrs := splitBackends(strings.Join(f.Backends, ","))
+	if len(addrs) == 0 {
+		return fmt.Errorf("no backend in %s", r.path)
+	}
+	r.modTime = info.ModTime()
+	if slices.Equal(addrs, r.addrs) {
+		return nil
+	}
+	r.addrs = addrs
+	fmt.Printf("backends from %s: %s\n", r.path, strings.Join(addrs, ", "))
+	return r.cc.UpdateState(backendsState(addrs))
+}
+
+func (r *fileResolver) ResolveNow(resolver.ResolveNowOptions) {
+	select {
+	case r.resolveNow <- struct{}{}:
+	default:
+	}
+}
+
+func (r *fileResolver) Close() {
+	close(r.done)
+}
+
+// backendReporter tells which backend served every call attempt, and counts them per backend
+type backendReporter struct {
+	// quiet only counts, for load mode
+	quiet bool
+
+	mu    sync.Mutex
+	calls map[string]int
+}
+
+func newBackendReporter(quiet bool) *backendReporter {
+	return &backendReporter{quiet: quiet, calls: map[string]int{}}
+}
+
+type backendKey struct{}
+
+type backendTag struct {
+	method  string
+	backend string
+	// replica is the x-replica identity sent by the demo server, from the header or the trailer
+	replica string
+}
+
+// replicaKey names the server replica that served a call
+const replicaKey = "x-replica"
+
+func (b *backendReporter) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	return context.WithValue(ctx, backendKey{}, &backendTag{method: info.FullMethodName})
+}
+
+func (b *backendReporter) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	tag, ok := ctx.Value(backendKey{}).(*backendTag)
+	if !ok {
+		return
+	}
+	switch s := s.(type) {
+	case *stats.OutHeader:
+		// only the client side OutHeader knows the address the attempt went to
+		if s.RemoteAddr != nil {
+			tag.backend = s.RemoteAddr.String()
+		}
+	case *stats.InHeader:
+		if v := s.Header.Get(replicaKey); len(v) > 0 {
+			tag.replica = v[0]
+		}
+	case *stats.InTrailer:
+		// Trailers-Only responses have no header
+		if v := s.Trailer.Get(replicaKey); len(v) > 0 {
+			tag.replica = v[0]
+		}
+	case *stats.End:
+		if tag.backend == "" {
+			// failed before a backend was picked, e.g. all of them down
+			tag.backend = "none"
+		}
+		b.mu.Lock()
+		b.calls[tag.backend]++
+		b.mu.Unlock()
+		if !b.quiet {
+			backend := tag.backend
+			if tag.replica != "" {
+				backend += " (" + tag.replica + ")"
+			}
+			fmt.Printf("%s served by %s: %v\n", tag.method, backend, status.Code(s.Error))
+		}
+	}
+}
+
+func (b *backendReporter) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (b *backendReporter) HandleConn(context.Context, stats.ConnStats) {}
+
+// summary prints the calls per backend
+func (b *backendReporter) summary() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	backends := make([]string, 0, len(b.calls))
+	for backend := range b.calls {
+		backends = append(backends, backend)
+	}
+	sort.Strings(backends)
+	fmt.Println("calls per backend:")
+	for _, backend := range backends {
+		fmt.Printf("  %s: %d\n", backend, b.calls[backend])
+	}
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+
+	"client/message/pb"
+
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCodecRoundTrip(t *testing.T) {
+	msg := &pb.UnaryResponse{
+		Response: "hello",
+		Meta:     &pb.MessageMeta{StreamId: "0123456789abcdef", Seq: 3, SentAtUnixNano: 1700000000000000000, Checksum: 0xdeadbeef},
+		Payload:  []byte{0, 1, 2, 0xff},
+	}
+	for _, name := range []string{"json", "demo"} {
+		t.Run(name, func(t *testing.T) {
+			codec := encoding.GetCodec(name)
+			if codec == nil {
+				t.Fatalf("codec %s is not registered", name)
+			}
+			data, err := codec.Marshal(msg)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := &pb.UnaryResponse{}
+			if err := codec.Unmarshal(data, got); err != nil {
+				t.Fatal(err)
+			}
+			if !proto.Equal(got, msg) {
+				t.Errorf("round trip gave %v, want %v", got, msg)
+			}
+		})
+	}
+}
+
+func TestDemoCodecFormat(t *testing.T) {
+	msg := &pb.UnaryRequest{Message: "hello"}
+	data, err := demoCodec{}.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wire, _ := proto.Marshal(msg)
+	if !bytes.Equal(data, append(append([]byte{}, demoMagic...), wire...)) {
+		t.Errorf("demo encoding %x, want the magic then %x", data, wire)
+	}
+	// the proto bytes alone are not demo messages
+	if err := (demoCodec{}).Unmarshal(wire, &pb.UnaryRequest{}); err == nil {
+		t.Error("Unmarshal without the magic succeeded")
+	}
+}
+
+func TestJSONCodecDiscardsUnknownFields(t *testing.T) {
+	// a newer server may answer with fields this client does not know yet
+	got := &pb.UnaryResponse{}
+	if err := (jsonCodec{}).Unmarshal([]byte(`{"response": "hello", "addedLater": 1}`), got); err != nil {
+		t.Fatal(err)
+	}
+	if got.GetResponse() != "hello" {
+		t.Errorf("response %q, want hello", got.GetResponse())
+	}
+}
+
+func TestCodecsRejectNonProto(t *testing.T) {
+	for _, codec := range []encoding.Codec{jsonCodec{}, demoCodec{}} {
+		if _, err := codec.Marshal("hello"); err == nil {
+			t.Errorf("%s codec marshaled a string", codec.Name())
+		}
+		if err := codec.Unmarshal([]byte("{}"), new(string)); err == nil {
+			t.Errorf("%s codec unmarshaled into a string", codec.Name())
+		}
+	}
+}
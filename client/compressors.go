@@ -0,0 +1,148 @@
+package main
+
+import (
+	"io"
+	"sync"
+
+	"github.com/golang/snappy"
+	"github.com/klauspost/compress/zstd"
+	"google.golang.org/grpc/encoding"
+	_ "google.golang.org/grpc/encoding/gzip"
+)
+
+// gzip registers itself, zstd and snappy are added next to it, so every one of them can be
+// picked by grpc-encoding and is advertised in grpc-accept-encoding.
+func init() {
+	encoding.RegisterCompressor(zstdCodec)
+	encoding.RegisterCompressor(&snappyCompressor{})
+}
+
+// zstdCompressor pools its encoders and decoders like the grpc gzip compressor,
+// both are synchronous with a concurrency of 1, so they hold no goroutines while pooled.
+type zstdCompressor struct {
+	// decoderLimit bounds the window and the decoded size of a message, set by setMaxRecvSize before any rpc
+	decoderLimit uint64
+	encoders     sync.Pool
+	decoders     sync.Pool
+}
+
+// zstdMinDecoderLimit is the window the streaming zstd encoders declare by default whatever the message
+// size, a smaller limit would fail small messages too
+const zstdMinDecoderLimit = 8 << 20
+
+// zstdCodec is the registered zstd compressor
+var zstdCodec = &zstdCompressor{decoderLimit: zstdMinDecoderLimit}
+
+// setMaxRecvSize ties the memory of the zstd decoders to -max-recv-size, so a small compressed
+// message can't make them allocate much more than the largest message accepted
+func (c *zstdCompressor) setMaxRecvSize(size int) {
+	c.decoderLimit = max(uint64(size), zstdMinDecoderLimit)
+}
+
+func (c *zstdCompressor) Name() string { return "zstd" }
+
+func (c *zstdCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	z, ok := c.encoders.Get().(*zstd.Encoder)
+	if !ok {
+		var err error
+		if z, err = zstd.NewWriter(w, zstd.WithEncoderConcurrency(1)); err != nil {
+			return nil, err
+		}
+	} else {
+		z.Reset(w)
+	}
+	return &zstdWriter{Encoder: z, pool: &c.encoders}, nil
+}
+
+func (c *zstdCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	z, ok := c.decoders.Get().(*zstd.Decoder)
+	if !ok {
+		var err error
+		z, err = zstd.NewReader(r,
+			zstd.WithDecoderConcurrency(1),
+			zstd.WithDecoderMaxWindow(c.decoderLimit),
+			zstd.WithDecoderMaxMemory(c.decoderLimit),
+		)
+		if err != nil {
+			return nil, err
+		}
+	} else if err := z.Reset(r); err != nil {
+		c.decoders.Put(z)
+		return nil, err
+	}
+	return &zstdReader{Decoder: z, pool: &c.decoders}, nil
+}
+
+type zstdWriter struct {
+	*zstd.Encoder
+	pool *sync.Pool
+}
+
+func (w *zstdWriter) Close() error {
+	defer w.pool.Put(w.Encoder)
+	return w.Encoder.Close()
+}
+
+type zstdReader struct {
+	*zstd.Decoder
+	pool *sync.Pool
+}
+
+func (r *zstdReader) Read(p []byte) (int, error) {
+	n, err := r.Decoder.Read(p)
+	if err == io.EOF {
+		r.pool.Put(r.Decoder)
+	}
+	return n, err
+}
+
+// snappyCompressor uses the snappy framing format, as the snappy compressors of the other grpc languages
+type snappyCompressor struct {
+	writers sync.Pool
+	readers sync.Pool
+}
+
+func (c *snappyCompressor) Name() string { return "snappy" }
+
+func (c *snappyCompressor) Compress(w io.Writer) (io.WriteCloser, error) {
+	s, ok := c.writers.Get().(*snappy.Writer)
+	if !ok {
+		s = snappy.NewBufferedWriter(w)
+	} else {
+		s.Reset(w)
+	}
+	return &snappyWriter{Writer: s, pool: &c.writers}, nil
+}
+
+func (c *snappyCompressor) Decompress(r io.Reader) (io.Reader, error) {
+	s, ok := c.readers.Get().(*snappy.Reader)
+	if !ok {
+		s = snappy.NewReader(r)
+	} else {
+		s.Reset(r)
+	}
+	return &snappyReader{Reader: s, pool: &c.readers}, nil
+}
+
+type snappyWriter struct {
+	*snappy.Writer
+	pool *sync.Pool
+}
+
+func (w *snappyWriter) Close() error {
+	defer w.pool.Put(w.Writer)
+	return w.Writer.Close()
+}
+
+type snappyReader struct {
+	*snappy.Reader
+	pool *sync.Pool
+}
+
+func (r *snappyReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err == io.EOF {
+		r.pool.Put(r.Reader)
+	}
+	return n, err
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+	"net"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/admin"
+	"google.golang.org/grpc/reflection"
+)
+
+// serveDebug serves the grpc admin services on addr, channelz there reports the channels,
+// subchannels and sockets of this client, so the streams sharing the main connection can be seen.
+// It returns a stop function.
+func serveDebug(addr string) (stop func(), err error) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+	s := grpc.NewServer()
+	cleanup, err := admin.Register(s)
+	if err != nil {
+		l.Close()
+		return nil, err
+	}
+	reflection.Register(s)
+	go func() {
+		if err := s.Serve(l); err != nil {
+			fmt.Printf("failed to serve debug: %v\n", err)
+		}
+	}()
+	fmt.Printf("serving channelz on %s\n", l.Addr())
+	return func() {
+		s.Stop()
+		cleanup()
+	}, nil
+}
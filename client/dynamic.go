@@ -0,0 +1,345 @@
+package main
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	rpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	rpbalpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// dynamicConfig is filled by the flags of the dynamic mode
+type dynamicConfig struct {
+	// Method is package.Service/Method, a leading slash or a dot before Method is accepted too
+	Method string
+	// Data is the JSON request, or several concatenated ones for client streams, @file reads it from a file, @- from stdin
+	Data string
+	// Protoset is a binary FileDescriptorSet, server reflection is used when empty
+	Protoset string
+	Headers  headerFlags
+}
+
+// headerFlags collects repeated -header "key: value" flags
+type headerFlags []string
+
+func (h *headerFlags) String() string { return strings.Join(*h, ", ") }
+
+func (h *headerFlags) Set(v string) error {
+	if !strings.Contains(v, ":") {
+		return fmt.Errorf("header must be key: value, got %q", v)
+	}
+	*h = append(*h, v)
+	return nil
+}
+
+func (h headerFlags) metadata() metadata.MD {
+	md := metadata.MD{}
+	for _, header := range h {
+		k, v, _ := strings.Cut(header, ":")
+		md.Append(strings.TrimSpace(k), strings.TrimSpace(v))
+	}
+	return md
+}
+
+func splitMethod(method string) (service, name string, err error) {
+	method = strings.TrimPrefix(method, "/")
+	if i := strings.LastIndex(method, "/"); i > 0 {
+		return method[:i], method[i+1:], nil
+	}
+	if i := strings.LastIndex(method, "."); i > 0 {
+		return method[:i], method[i+1:], nil
+	}
+	return "", "", fmt.Errorf("invalid method %q, expected package.Service/Method", method)
+}
+
+// runDynamic calls any method described by a FileDescriptorSet or by the server reflection,
+// with dynamicpb messages built from JSON, and returns the process exit code.
+func runDynamic(conn *grpc.ClientConn, cfg dynamicConfig) int {
+	serviceName, methodName, err := splitMethod(cfg.Method)
+	if err != nil {
+		fmt.Println(err)
+		return exitUsage
+	}
+	var files *protoregistry.Files
+	if cfg.Protoset != "" {
+		files, err = loadProtoset(cfg.Protoset)
+	} else {
+		files, err = resolveByReflection(conn, serviceName)
+	}
+	if err != nil {
+		fmt.Printf("failed to resolve descriptors: %v\n", err)
+		return exitFailure
+	}
+	d, err := files.FindDescriptorByName(protoreflect.FullName(serviceName))
+	if err != nil {
+		fmt.Printf("service %s not found: %v\n", serviceName, err)
+		return exitUsage
+	}
+	service, ok := d.(protoreflect.ServiceDescriptor)
+	if !ok {
+		fmt.Printf("%s is not a service\n", serviceName)
+		return exitUsage
+	}
+	method := service.Methods().ByName(protoreflect.Name(methodName))
+	if method == nil {
+		fmt.Printf("method %s not found in %s\n", methodName, serviceName)
+		return exitUsage
+	}
+
+	types := dynamicpb.NewTypes(files)
+	requests, err := parseRequests(cfg.Data, method.Input(), types)
+	if err != nil {
+		fmt.Printf("invalid request data: %v\n", err)
+		return exitUsage
+	}
+	if !method.IsStreamingClient() && len(requests) != 1 {
+		fmt.Printf("%s takes exactly one request, got %d\n", method.FullName(), len(requests))
+		return exitUsage
+	}
+
+	err = invokeDynamic(conn, method, requests, types, cfg.Headers.metadata())
+	if err != nil {
+		fmt.Println(err)
+		return exitFailure
+	}
+	return exitOK
+}
+
+func invokeDynamic(conn *grpc.ClientConn, method protoreflect.MethodDescriptor, requests []proto.Message, types *dynamicpb.Types, md metadata.MD) error {
+	ctx, cancel := context.WithCancel(metadata.NewOutgoingContext(context.Background(), md))
+	defer cancel()
+	fullMethod := fmt.Sprintf("/%s/%s", method.Parent().FullName(), method.Name())
+	desc := &grpc.StreamDesc{
+		StreamName:    string(method.Name()),
+		ClientStreams: method.IsStreamingClient(),
+		ServerStreams: method.IsStreamingServer(),
+	}
+	stream, err := conn.NewStream(ctx, desc, fullMethod)
+	if err != nil {
+		return err
+	}
+	go func() {
+		for _, req := range requests {
+			if err := stream.SendMsg(req); err != nil {
+				return // the real error is returned by RecvMsg
+			}
+		}
+		stream.CloseSend()
+	}()
+
+	if header, err := stream.Header(); err == nil {
+		printMetadata("response header", header)
+	}
+	marshal := protojson.MarshalOptions{Resolver: types}
+	for {
+		resp := dynamicpb.NewMessage(method.Output())
+		err := stream.RecvMsg(resp)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			printMetadata("response trailer", stream.Trailer())
+			return err
+		}
+		out, err := marshal.Marshal(resp)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+	}
+	printMetadata("response trailer", stream.Trailer())
+	return nil
+}
+
+// printMetadata prints one key per line, binary values are base64 encoded like on the wire
+func printMetadata(kind string, md metadata.MD) {
+	for k, vs := range md {
+		for _, v := range vs {
+			if strings.HasSuffix(k, "-bin") {
+				v = base64.StdEncoding.EncodeToString([]byte(v))
+			}
+			fmt.Printf("%s %s: %s\n", kind, k, v)
+		}
+	}
+}
+
+// parseRequests reads a sequence of JSON objects into messages of type input
+func parseRequests(data string, input protoreflect.MessageDescriptor, types *dynamicpb.Types) ([]proto.Message, error) {
+	var r io.Reader = strings.NewReader(data)
+	if path, ok := strings.CutPrefix(data, "@"); ok {
+		if path == "-" {
+			r = os.Stdin
+		} else {
+			f, err := os.Open(path)
+			if err != nil {
+				return nil, err
+			}
+			defer f.Close()
+			r = f
+		}
+	}
+	unmarshal := protojson.UnmarshalOptions{Resolver: types}
+	decoder := json.NewDecoder(r)
+	var requests []proto.Message
+	for {
+		var raw json.RawMessage
+		err := decoder.Decode(&raw)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		msg := dynamicpb.NewMessage(input)
+		if err := unmarshal.Unmarshal(raw, msg); err != nil {
+			return nil, err
+		}
+		requests = append(requests, msg)
+	}
+	if len(requests) == 0 {
+		// no data is an empty request, handy for methods without fields
+		requests = append(requests, dynamicpb.NewMessage(input))
+	}
+	return requests, nil
+}
+
+func loadProtoset(path string) (*protoregistry.Files, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	set := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(data, set); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return protodesc.NewFiles(set)
+}
+
+// reflectionClient asks the server for the files defining a symbol or having a name,
+// it returns them as serialized FileDescriptorProtos
+type reflectionClient func(req reflectionRequest) ([][]byte, error)
+
+type reflectionRequest struct {
+	symbol   string
+	filename string
+}
+
+func newReflectionClientV1(ctx context.Context, conn *grpc.ClientConn) (reflectionClient, error) {
+	stream, err := rpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return func(req reflectionRequest) ([][]byte, error) {
+		msg := &rpb.ServerReflectionRequest{MessageRequest: &rpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: req.symbol}}
+		if req.filename != "" {
+			msg.MessageRequest = &rpb.ServerReflectionRequest_FileByFilename{FileByFilename: req.filename}
+		}
+		if err := stream.Send(msg); err != nil {
+			return nil, err
+		}
+		resp, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		if e := resp.GetErrorResponse(); e != nil {
+			return nil, status.Error(codes.Code(e.GetErrorCode()), e.GetErrorMessage())
+		}
+		return resp.GetFileDescriptorResponse().GetFileDescriptorProto(), nil
+	}, nil
+}
+
+func newReflectionClientV1Alpha(ctx context.Context, conn *grpc.ClientConn) (reflectionClient, error) {
+	stream, err := rpbalpha.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return func(req reflectionRequest) ([][]byte, error) {
+		msg := &rpbalpha.ServerReflectionRequest{MessageRequest: &rpbalpha.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: req.symbol}}
+		if req.filename != "" {
+			msg.MessageRequest = &rpbalpha.ServerReflectionRequest_FileByFilename{FileByFilename: req.filename}
+		}
+		if err := stream.Send(msg); err != nil {
+			return nil, err
+		}
+		resp, err := stream.Recv()
+		if err != nil {
+			return nil, err
+		}
+		if e := resp.GetErrorResponse(); e != nil {
+			return nil, status.Error(codes.Code(e.GetErrorCode()), e.GetErrorMessage())
+		}
+		return resp.GetFileDescriptorResponse().GetFileDescriptorProto(), nil
+	}, nil
+}
+
+// resolveByReflection fetches the file of symbol and all its dependencies,
+// through reflection v1 and falling back to v1alpha for older servers.
+func resolveByReflection(conn *grpc.ClientConn, symbol string) (*protoregistry.Files, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	client, err := newReflectionClientV1(ctx, conn)
+	var files [][]byte
+	if err == nil {
+		files, err = client(reflectionRequest{symbol: symbol})
+	}
+	if status.Code(err) == codes.Unimplemented {
+		client, err = newReflectionClientV1Alpha(ctx, conn)
+		if err == nil {
+			files, err = client(reflectionRequest{symbol: symbol})
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	seen := map[string]bool{}
+	for len(files) > 0 {
+		var missing []string
+		for _, data := range files {
+			fd := &descriptorpb.FileDescriptorProto{}
+			if err := proto.Unmarshal(data, fd); err != nil {
+				return nil, err
+			}
+			if seen[fd.GetName()] {
+				continue
+			}
+			seen[fd.GetName()] = true
+			set.File = append(set.File, fd)
+			missing = append(missing, fd.GetDependency()...)
+		}
+		files = nil
+		for _, name := range missing {
+			if seen[name] {
+				continue
+			}
+			// the server usually sends the dependencies along, ask only for the ones left
+			deps, err := client(reflectionRequest{filename: name})
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch %s: %w", name, err)
+			}
+			files = append(files, deps...)
+		}
+	}
+	if len(set.File) == 0 {
+		return nil, errors.New("server returned no descriptor")
+	}
+	return protodesc.NewFiles(set)
+}
@@ -0,0 +1,307 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+
+	"client/message/pb"
+
+	"github.com/HdrHistogram/hdrhistogram-go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// loadConfig is filled by the -load-* flags
+type loadConfig struct {
+	RPC         string
+	Concurrency int
+	// QPS > 0 runs open-loop at a fixed rate, otherwise every worker calls back to back (closed-loop)
+	QPS float64
+	// Messages is the number of messages per client or bidirectional stream
+	Messages int
+	Duration time.Duration
+	// ExpectedInterval is used to correct closed-loop latencies for coordinated omission
+	ExpectedInterval time.Duration
+}
+
+// latencyHistogram records latencies in microseconds, up to one minute. A longer latency is recorded
+// as the highest trackable value and counted as out of range, so no sample is left out of the report.
+type latencyHistogram struct {
+	*hdrhistogram.Histogram
+	outOfRange int64
+}
+
+func newHistogram() *latencyHistogram {
+	return &latencyHistogram{Histogram: hdrhistogram.New(1, int64(time.Minute/time.Microsecond), 3)}
+}
+
+// record adds latency d, expected > 0 corrects it for coordinated omission
+func (h *latencyHistogram) record(d, expected time.Duration) {
+	v := max(d.Microseconds(), 0)
+	if highest := h.HighestTrackableValue(); v > highest {
+		v = highest
+		h.outOfRange++
+	}
+	if err := h.RecordCorrectedValue(v, expected.Microseconds()); err != nil {
+		h.outOfRange++
+	}
+}
+
+func (h *latencyHistogram) merge(other *latencyHistogram) {
+	h.Merge(other.Histogram)
+	h.outOfRange += other.outOfRange
+}
+
+type loadWorker struct {
+	calls    *latencyHistogram
+	messages *latencyHistogram
+	errors   map[codes.Code]int
+}
+
+// runLoad drives cfg.Concurrency workers against one rpc and prints the latency report,
+// it returns the process exit code.
+func runLoad(client pb.StreamingServiceClient, cfg loadConfig) int {
+	switch cfg.RPC {
+	case "UnaryRPC", "ClientStreamRPC", "ServerStreamRPC", "BidirectionalStreamRPC":
+	default:
+		fmt.Printf("unknown load rpc: %s\n", cfg.RPC)
+		return exitUsage
+	}
+	if cfg.Concurrency <= 0 || cfg.Duration <= 0 {
+		fmt.Println("-concurrency and -duration must be positive in load mode")
+		return exitUsage
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Duration)
+	defer cancel()
+
+	// in open-loop mode the scheduler hands out the intended start time of every call,
+	// measuring from it instead of the actual start keeps queueing time in the latency
+	var schedule chan time.Time
+	if cfg.QPS > 0 {
+		schedule = make(chan time.Time, cfg.Concurrency)
+		go func() {
+			defer close(schedule)
+			interval := time.Duration(float64(time.Second) / cfg.QPS)
+			start := time.Now()
+			for i := 0; ; i++ {
+				intended := start.Add(time.Duration(i) * interval)
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(time.Until(intended)):
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case schedule <- intended:
+				}
+			}
+		}()
+	}
+
+	workers := make([]*loadWorker, cfg.Concurrency)
+	var wg sync.WaitGroup
+	start := time.Now()
+	for i := range workers {
+		w := &loadWorker{calls: newHistogram(), messages: newHistogram(), errors: map[codes.Code]int{}}
+		workers[i] = w
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.run(ctx, client, cfg, schedule)
+		}()
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	calls, messages := newHistogram(), newHistogram()
+	errors := map[codes.Code]int{}
+	for _, w := range workers {
+		calls.merge(w.calls)
+		messages.merge(w.messages)
+		for code, n := range w.errors {
+			errors[code] += n
+		}
+	}
+	return printLoadReport(cfg, elapsed, calls, messages, errors)
+}
+
+func (w *loadWorker) run(ctx context.Context, client pb.StreamingServiceClient, cfg loadConfig, schedule <-chan time.Time) {
+	for {
+		var begin time.Time
+		if schedule != nil {
+			intended, ok := <-schedule
+			if !ok {
+				return
+			}
+			begin = intended
+		} else {
+			if ctx.Err() != nil {
+				return
+			}
+			begin = time.Now()
+		}
+		err := loadCall(ctx, client, cfg, func(d time.Duration) {
+			w.messages.record(d, 0)
+		})
+		if ctx.Err() != nil {
+			// calls cut by the end of the run are neither counted as success nor failure
+			return
+		}
+		var expected time.Duration
+		if schedule == nil {
+			expected = cfg.ExpectedInterval
+		}
+		w.calls.record(time.Since(begin), expected)
+		if err != nil {
+			w.errors[status.Code(err)]++
+		}
+	}
+}
+
+// loadCall makes one call of cfg.RPC, onMessage gets the latency of every stream message:
+// the Send duration for client streams, the round-trip time of every request for bidirectional streams,
+// the time since the request or the previous response for server streams.
+// Responses out of sequence fail the call with DataLoss.
+func loadCall(ctx context.Context, client pb.StreamingServiceClient, cfg loadConfig, onMessage func(time.Duration)) error {
+	// a call ending early, e.g. on a sequence error, cancels its stream instead of leaving it open until the end of the run
+	ctx, cancel := context.WithCancel(metadata.NewOutgoingContext(ctx, uniformHeader("load")))
+	defer cancel()
+	sender, checker := newSeqSender(), newSeqChecker()
+	switch cfg.RPC {
+	case "UnaryRPC":
+		msg := "Hello, Unary RPC!"
+		payload := payloads.next()
+		resp, err := client.UnaryRPC(ctx, &pb.UnaryRequest{Message: msg, Payload: payload, Meta: sender.next(msg, payload, nil)})
+		if err != nil {
+			return err
+		}
+		return seqError(checker.check(resp.GetMeta(), resp.GetResponse(), resp.GetPayload()))
+	case "ClientStreamRPC":
+		stream, err := client.ClientStreamRPC(ctx)
+		if err != nil {
+			return err
+		}
+		for i := 0; i < cfg.Messages; i++ {
+			sent := time.Now()
+			msg := fmt.Sprintf("Client Stream Message %d", i)
+			payload := payloads.next()
+			if err := stream.Send(&pb.ClientStreamRequest{Message: msg, Payload: payload, Meta: sender.next(msg, payload, nil)}); err != nil {
+				break // the real error is returned by CloseAndRecv
+			}
+			onMessage(time.Since(sent))
+		}
+		resp, err := stream.CloseAndRecv()
+		if err != nil {
+			return err
+		}
+		return seqError(checker.check(resp.GetMeta(), resp.GetResponse(), resp.GetPayload()))
+	case "ServerStreamRPC":
+		last := time.Now()
+		msg := "Hello, Server Stream RPC!"
+		payload := payloads.next()
+		stream, err := client.ServerStreamRPC(ctx, &pb.ServerStreamRequest{Message: msg, Payload: payload, Meta: sender.next(msg, payload, nil)})
+		if err != nil {
+			return err
+		}
+		for {
+			resp, err := stream.Recv()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := checker.check(resp.GetMeta(), resp.GetResponse(), resp.GetPayload()); err != nil {
+				return seqError(err)
+			}
+			onMessage(time.Since(last))
+			last = time.Now()
+		}
+	case "BidirectionalStreamRPC":
+		stream, err := client.BidirectionalStreamRPC(ctx)
+		if err != nil {
+			return err
+		}
+		var sending sync.WaitGroup
+		sending.Add(1)
+		// the sender is canceled before it is waited for, a Send blocked by flow control returns then
+		defer func() {
+			cancel()
+			sending.Wait()
+		}()
+		go func() {
+			defer sending.Done()
+			for i := 0; i < cfg.Messages; i++ {
+				msg := fmt.Sprintf("Bidirectional Stream Message %d", i)
+				payload := payloads.next()
+				if err := stream.Send(&pb.BidirectionalStreamRequest{Message: msg, Payload: payload, Meta: sender.next(msg, payload, nil)}); err != nil {
+					return // the real error is returned by Recv
+				}
+			}
+			stream.CloseSend()
+		}()
+		for {
+			resp, err := stream.Recv()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+			if err := checker.check(resp.GetMeta(), resp.GetResponse(), resp.GetPayload()); err != nil {
+				return seqError(err)
+			}
+			// the first response to a request echoes its send time
+			if sentAt := resp.GetMeta().GetAckSentAtUnixNano(); sentAt > 0 {
+				onMessage(time.Since(time.Unix(0, sentAt)))
+			}
+		}
+	}
+	return nil
+}
+
+func printLoadReport(cfg loadConfig, elapsed time.Duration, calls, messages *latencyHistogram, errors map[codes.Code]int) int {
+	loop := "closed-loop"
+	if cfg.QPS > 0 {
+		loop = fmt.Sprintf("open-loop at %.1f qps", cfg.QPS)
+	}
+	failed := 0
+	for _, n := range errors {
+		failed += n
+	}
+	fmt.Printf("%s, %d workers, %s, %v\n", cfg.RPC, cfg.Concurrency, loop, elapsed.Round(time.Millisecond))
+	fmt.Printf("calls: %d total, %d failed, %.1f calls/s\n", calls.TotalCount(), failed, float64(calls.TotalCount())/elapsed.Seconds())
+	printHistogram("call latency", calls)
+	if messages.TotalCount() > 0 {
+		printHistogram("message latency", messages)
+	}
+	if failed > 0 {
+		codeList := make([]codes.Code, 0, len(errors))
+		for code := range errors {
+			codeList = append(codeList, code)
+		}
+		sort.Slice(codeList, func(i, j int) bool { return codeList[i] < codeList[j] })
+		fmt.Println("errors by code:")
+		for _, code := range codeList {
+			fmt.Printf("  %v: %d\n", code, errors[code])
+		}
+		return exitFailure
+	}
+	return exitOK
+}
+
+func printHistogram(name string, h *latencyHistogram) {
+	us := func(v int64) time.Duration { return time.Duration(v) * time.Microsecond }
+	fmt.Printf("%s (%d samples): p50=%v p90=%v p99=%v p999=%v max=%v\n", name, h.TotalCount(),
+		us(h.ValueAtQuantile(50)), us(h.ValueAtQuantile(90)), us(h.ValueAtQuantile(99)), us(h.ValueAtQuantile(99.9)), us(h.Max()))
+	if h.outOfRange > 0 {
+		fmt.Printf("  %d samples over %v, recorded as %v\n", h.outOfRange, us(h.HighestTrackableValue()), us(h.HighestTrackableValue()))
+	}
+}
@@ -13,7 +13,7 @@ import (
 	"time"
 
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding"
 	"google.golang.org/grpc/metadata"
 )
 
@@ -22,26 +22,206 @@ var ServerDelay = 10
 func main() {
 	port := "38888"
 	host := "localhost"
+	mode := "interactive"
+	scenario := ""
+	scenarioFile := ""
+	count := 1
+	interval := time.Duration(0)
+	duration := time.Duration(0)
+	tlsCfg := tlsConfig{}
+	tracing := tracingConfig{Exporter: "none", File: "client-traces.json", OTLPEndpoint: "localhost:4317"}
+	load := loadConfig{RPC: "UnaryRPC", Concurrency: 1, Messages: 3}
+	dynamic := dynamicConfig{}
+	debugAddr := ""
+	payloadCfg := payloadConfig{Distribution: "fixed", Alpha: 1.5, Content: "random"}
+	var maxRecvSize, maxSendSize byteSize
+	backends := ""
+	codec := ""
+	compression := ""
+	svcConfig := serviceConfig{Methods: "message.StreamingService", RetryInitialBackoff: 100 * time.Millisecond, RetryMaxBackoff: time.Second,
+		RetryBackoffMultiplier: 2, RetryCodes: "UNAVAILABLE", HedgingDelay: 100 * time.Millisecond, HedgingCodes: "UNAVAILABLE"}
 	flag.StringVar(&port, "port", port, "The server port")
 	flag.StringVar(&host, "host", host, "The server host")
+	flag.StringVar(&backends, "backends", backends, "Backends instead of -host and -port: host1:port1,host2:port2, @file for a watched YAML/JSON file or a target such as dns:///host:port")
+	flag.StringVar(&svcConfig.LB, "lb", svcConfig.LB, "Load balancing policy of the backends: pick_first, round_robin or weighted_round_robin")
 	flag.IntVar(&ServerDelay, "delay", ServerDelay, "The server delay, unit: ms")
+	flag.StringVar(&mode, "mode", mode, "Run mode: interactive, scenario, load, dynamic or types")
+	flag.StringVar(&scenario, "scenario", scenario, "Comma separated scenarios for scenario mode, one of: "+strings.Join(scenarioNames, ", "))
+	flag.StringVar(&scenarioFile, "scenario-file", scenarioFile, "YAML or JSON file with the steps to run in scenario mode")
+	flag.IntVar(&count, "count", count, "How many times to run the scenarios, 0 means until -duration is reached, the default when -duration is set alone")
+	flag.DurationVar(&interval, "interval", interval, "Wait time between two scenario runs")
+	flag.DurationVar(&duration, "duration", duration, "Stop running scenarios after this duration, 0 means no limit")
+	flag.BoolVar(&tlsCfg.Enabled, "tls", tlsCfg.Enabled, "Connect with TLS, implied by the other -tls-* flags")
+	flag.StringVar(&tlsCfg.CAFile, "tls-ca", tlsCfg.CAFile, "Root CA file to verify the server, system roots by default")
+	flag.StringVar(&tlsCfg.CertFile, "tls-cert", tlsCfg.CertFile, "Client certificate file for mTLS")
+	flag.StringVar(&tlsCfg.KeyFile, "tls-key", tlsCfg.KeyFile, "Client key file for mTLS")
+	flag.StringVar(&tlsCfg.ServerName, "tls-server-name", tlsCfg.ServerName, "Override the server name used to verify the server certificate")
+	flag.StringVar(&tracing.Exporter, "trace-exporter", tracing.Exporter, "OpenTelemetry span exporter: none, stdout, file or otlp")
+	flag.StringVar(&tracing.File, "trace-file", tracing.File, "JSON file the file trace exporter appends spans to")
+	flag.StringVar(&tracing.OTLPEndpoint, "trace-otlp-endpoint", tracing.OTLPEndpoint, "OTLP/gRPC endpoint of the otlp trace exporter")
+	flag.BoolVar(&tracing.MessageEvents, "trace-message-events", tracing.MessageEvents, "Record every stream message as a span event")
+	flag.StringVar(&load.RPC, "load-rpc", load.RPC, "The StreamingService method called in load mode")
+	flag.IntVar(&load.Concurrency, "concurrency", load.Concurrency, "Number of concurrent workers in load mode")
+	flag.Float64Var(&load.QPS, "qps", load.QPS, "Target calls per second over all workers in load mode, 0 means closed-loop")
+	flag.IntVar(&load.Messages, "messages", load.Messages, "Messages sent per client or bidirectional stream in load mode")
+	flag.DurationVar(&load.ExpectedInterval, "expected-interval", load.ExpectedInterval, "Expected interval between calls of one closed-loop worker, used to correct coordinated omission")
+	flag.StringVar(&dynamic.Method, "method", dynamic.Method, "Full method called in dynamic mode, e.g. message.StreamingService/UnaryRPC")
+	flag.StringVar(&dynamic.Data, "data", dynamic.Data, "JSON request of dynamic mode, concatenated objects for client streams, @file reads a file and @- stdin")
+	flag.StringVar(&dynamic.Protoset, "protoset", dynamic.Protoset, "FileDescriptorSet file describing the method in dynamic mode (protoc --include_imports -o), server reflection by default")
+	flag.Var(&dynamic.Headers, "header", "Request metadata \"key: value\" of dynamic mode, can be repeated")
+	flag.StringVar(&payloadCfg.Distribution, "payload-dist", payloadCfg.Distribution, "Distribution of the request payload sizes: fixed, uniform, normal or pareto")
+	flag.Var(&payloadCfg.Size, "payload-size", "Request payload size, the mean of normal sizes and the minimum of pareto sizes, e.g. 512, 64K, 16M")
+	flag.Var(&payloadCfg.Min, "payload-min", "Smallest request payload size")
+	flag.Var(&payloadCfg.Max, "payload-max", "Largest request payload size, required by the uniform distribution")
+	flag.Var(&payloadCfg.StdDev, "payload-stddev", "Standard deviation of normal request payload sizes")
+	flag.Float64Var(&payloadCfg.Alpha, "payload-alpha", payloadCfg.Alpha, "Shape of pareto request payload sizes, the lower the longer the tail")
+	flag.StringVar(&payloadCfg.Content, "payload-content", payloadCfg.Content, "Request payload content: random, text (compressible) or zeros")
+	flag.Var(&maxRecvSize, "max-recv-size", "Largest response message the client accepts, 4M by default")
+	flag.Var(&maxSendSize, "max-send-size", "Largest request message the client sends, unlimited by default")
+	flag.StringVar(&codec, "codec", codec, "Content-subtype of every call: proto, json or demo, plain application/grpc by default")
+	flag.StringVar(&compression, "compression", compression, "Request compressor of every call: gzip, zstd, snappy or identity, uncompressed by default")
+	flag.StringVar(&svcConfig.File, "service-config", svcConfig.File, "JSON service config file of the channel, instead of the -retry-*/-hedging-* flags")
+	flag.StringVar(&svcConfig.Methods, "policy-methods", svcConfig.Methods, "Comma separated services or service/method the retry or hedging policy applies to")
+	flag.IntVar(&svcConfig.RetryMaxAttempts, "retry-max-attempts", svcConfig.RetryMaxAttempts, "Retry policy attempts, including the first one, at most 5, no retry policy when 0")
+	flag.DurationVar(&svcConfig.RetryInitialBackoff, "retry-initial-backoff", svcConfig.RetryInitialBackoff, "Retry policy backoff before the first retry")
+	flag.DurationVar(&svcConfig.RetryMaxBackoff, "retry-max-backoff", svcConfig.RetryMaxBackoff, "Retry policy largest backoff")
+	flag.Float64Var(&svcConfig.RetryBackoffMultiplier, "retry-backoff-multiplier", svcConfig.RetryBackoffMultiplier, "Retry policy backoff growth after every retry")
+	flag.StringVar(&svcConfig.RetryCodes, "retry-codes", svcConfig.RetryCodes, "Comma separated status codes retried by the retry policy")
+	flag.IntVar(&svcConfig.HedgingMaxAttempts, "hedging-max-attempts", svcConfig.HedgingMaxAttempts, "Hedging policy attempts of unary calls, including the first one, no hedging policy when 0")
+	flag.DurationVar(&svcConfig.HedgingDelay, "hedging-delay", svcConfig.HedgingDelay, "Hedging policy delay between two attempts, 0 sends them all at once")
+	flag.StringVar(&svcConfig.HedgingCodes, "hedging-codes", svcConfig.HedgingCodes, "Comma separated non-fatal status codes of the hedging policy, they send the next attempt at once")
+	flag.BoolVar(&svcConfig.LogAttempts, "log-attempts", svcConfig.LogAttempts, "Print every attempt of every call with its status")
+	flag.StringVar(&debugAddr, "debug-addr", debugAddr, "Address of a grpc listener serving channelz about this client, e.g. :38890, disabled when empty")
 	flag.Parse()
-	conn, err := grpc.NewClient(fmt.Sprintf("%s:%s", host, port), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	load.Duration = duration
+	if duration > 0 && !flagSet("count") {
+		// -duration alone runs the scenarios until it is reached
+		count = 0
+	}
+	if mode == "interactive" && (scenario != "" || scenarioFile != "") {
+		mode = "scenario"
+	}
+	if mode == "interactive" && dynamic.Method != "" {
+		mode = "dynamic"
+	}
+	if mode != "interactive" && mode != "scenario" && mode != "load" && mode != "dynamic" && mode != "types" {
+		fmt.Printf("unknown mode: %s\n", mode)
+		os.Exit(exitUsage)
+	}
+	generator, err := newPayloadGenerator(payloadCfg)
+	if err != nil {
+		fmt.Println(err)
+		os.Exit(exitUsage)
+	}
+	payloads = generator
+	creds, err := tlsCfg.transportCredentials()
+	if err != nil {
+		fmt.Printf("failed to setup TLS: %v\n", err)
+		os.Exit(exitUsage)
+	}
+	dialOpts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if codec != "" {
+		if encoding.GetCodec(codec) == nil {
+			fmt.Printf("unknown codec: %s\n", codec)
+			os.Exit(exitUsage)
+		}
+		// scenario file steps can still pick their own codec
+		dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(grpc.CallContentSubtype(codec)))
+	}
+	if compression != "" {
+		if compression != encoding.Identity && encoding.GetCompressor(compression) == nil {
+			fmt.Printf("unknown compressor: %s\n", compression)
+			os.Exit(exitUsage)
+		}
+		dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(grpc.UseCompressor(compression)))
+	}
+	policyOpts, err := svcConfig.dialOptions()
+	if err != nil {
+		fmt.Printf("invalid service config: %v\n", err)
+		os.Exit(exitUsage)
+	}
+	dialOpts = append(dialOpts, policyOpts...)
+	if maxRecvSize > 0 {
+		dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(grpc.MaxCallRecvMsgSize(int(maxRecvSize))))
+		zstdCodec.setMaxRecvSize(int(maxRecvSize))
+	}
+	if maxSendSize > 0 {
+		dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(grpc.MaxCallSendMsgSize(int(maxSendSize))))
+	}
+	traceHandler, shutdownTracing, err := tracing.setup("grpc-stream-demo-client")
+	if err != nil {
+		fmt.Printf("failed to setup tracing: %v\n", err)
+		os.Exit(exitUsage)
+	}
+	defer shutdownTracing()
+	if traceHandler != nil {
+		dialOpts = append(dialOpts, grpc.WithStatsHandler(traceHandler))
+	}
+	if debugAddr != "" {
+		stopDebug, err := serveDebug(debugAddr)
+		if err != nil {
+			fmt.Printf("failed to serve debug: %v\n", err)
+			os.Exit(exitUsage)
+		}
+		defer stopDebug()
+	}
+	target := fmt.Sprintf("%s:%s", host, port)
+	var reporter *backendReporter
+	if backends != "" {
+		if target, err = backendsTarget(backends); err != nil {
+			fmt.Println(err)
+			os.Exit(exitUsage)
+		}
+		// load mode makes too many calls to print each of them
+		reporter = newBackendReporter(mode == "load")
+		dialOpts = append(dialOpts, grpc.WithResolvers(staticResolverBuilder{}, fileResolverBuilder{}), grpc.WithStatsHandler(reporter))
+	}
+	conn, err := grpc.NewClient(target, dialOpts...)
 	if err != nil {
 		log.Fatalf("did not connect: %v", err)
 	}
 	defer conn.Close()
 
+	if mode == "dynamic" {
+		// the dynamic client may target any server, so it skips the StreamingService keep-alive stream below
+		code := runDynamic(conn, dynamic)
+		conn.Close()
+		if reporter != nil {
+			reporter.summary()
+		}
+		shutdownTracing()
+		os.Exit(code)
+	}
+
 	// 这里在启动时创建一个 stream service client 做永久 client 保活，并维持连接，在同一个连接里推送消息
 	// 用以模拟大多数 grpc client sdk 的行为，所以所有流量在启动时都会有一个 client stream 请求
 	client := pb.NewStreamingServiceClient(conn)
 
 	streamingClient := &StreamingClient{
-		recvChan:   make(chan string),
+		recvChan:   make(chan clientStreamMessage),
 		recvClient: client,
 	}
 	streamingClient.initOneClientStream()
 
+	if mode == "scenario" || mode == "load" || mode == "types" {
+		var code int
+		if mode == "types" {
+			code = runTypes(pb.NewTypesServiceClient(conn), codec)
+		} else if mode == "load" {
+			code = runLoad(client, load)
+		} else if scenarioFile != "" {
+			code = runScenarioFile(client, scenarioFile)
+		} else {
+			code = streamingClient.runScenarios(client, scenario, count, interval, duration)
+		}
+		conn.Close()
+		if reporter != nil {
+			reporter.summary()
+		}
+		shutdownTracing()
+		os.Exit(code)
+	}
+
 	reader := bufio.NewReader(os.Stdin)
 	for {
 		fmt.Println("Select the communication mode:")
@@ -56,52 +236,80 @@ func main() {
 		choice, _ := reader.ReadString('\n')
 		choice = strings.TrimSpace(choice)
 
+		var err error
 		switch choice {
 		case "1":
-			streamingClient.unaryRPC(client)
+			err = streamingClient.unaryRPC(client)
 		case "2":
-			streamingClient.clientStreamRPC(client)
+			err = streamingClient.clientStreamRPC(client)
 		case "3":
-			streamingClient.serverStreamRPC(client)
+			err = streamingClient.serverStreamRPC(client)
 		case "4":
-			streamingClient.bidirectionalStreamRPC(client)
+			err = streamingClient.bidirectionalStreamRPC(client)
 		case "5":
-			streamingClient.clientRepeatedStream()
+			err = streamingClient.clientRepeatedStream()
 		case "6":
 			fmt.Println("Exiting...")
-			streamingClient.recvChan <- "exit"
+			streamingClient.stopClientStream()
 			return
 		default:
 			fmt.Println("Invalid choice. Please try again.")
 		}
+		if err != nil {
+			log.Println(err)
+		}
 	}
 }
 
+// flagSet reports whether the flag name was given on the command line
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
 type StreamingClient struct {
-	recvChan   chan string
+	recvChan   chan clientStreamMessage
 	recvClient pb.StreamingServiceClient
 }
 
+// clientStreamMessage is a message for the long-lived client stream, its send result goes to sent,
+// a nil sent asks the stream to exit
+type clientStreamMessage struct {
+	text string
+	sent chan error
+}
+
+// clientStreamTimeout bounds how long a message waits for the long-lived client stream, e.g. while it reconnects
+const clientStreamTimeout = 3 * time.Second
+
 func (s *StreamingClient) initOneClientStream() {
 	go func() {
 	streamLoop:
 		for {
 			stream, err := s.recvClient.ClientStreamRPC(context.Background())
 			if err != nil {
-				fmt.Printf("failed to call ClientStreamRPC: %v", err)
+				fmt.Printf("failed to call ClientStreamRPC: %v\n", err)
+				time.Sleep(time.Second)
 				continue streamLoop
 			}
-			for s := range s.recvChan {
-				if s == "exit" {
+			sender := newSeqSender()
+			for m := range s.recvChan {
+				if m.sent == nil {
 					// not a good impl but for exit demo
 					fmt.Println("receive exit")
 					break
 				}
-				if err := stream.Send(&pb.ClientStreamRequest{Message: s}); err != nil {
-					fmt.Printf("failed to send ClientStreamRequest: %v", err)
+				if err := stream.Send(&pb.ClientStreamRequest{Message: m.text, Meta: sender.next(m.text, nil, nil)}); err != nil {
+					m.sent <- fmt.Errorf("failed to send ClientStreamRequest: %w", err)
 					continue streamLoop
 				}
-				fmt.Printf("Sending message: %s\n", s)
+				fmt.Printf("Sending message: %s\n", m.text)
+				m.sent <- nil
 			}
 			stream.CloseAndRecv()
 			break
@@ -117,80 +325,132 @@ var uniformHeader = func(s string) metadata.MD {
 	})
 }
 
-func (s *StreamingClient) unaryRPC(client pb.StreamingServiceClient) {
+func (s *StreamingClient) unaryRPC(client pb.StreamingServiceClient) error {
 	// Unary unaryRPC
 	ctx := metadata.NewOutgoingContext(context.Background(), uniformHeader("unaryRPC"))
-	unaryResponse, err := client.UnaryRPC(ctx, &pb.UnaryRequest{Message: "Hello, Unary RPC!"})
+	msg := "Hello, Unary RPC!"
+	payload := payloads.next()
+	unaryResponse, err := client.UnaryRPC(ctx, &pb.UnaryRequest{Message: msg, Payload: payload, Meta: newSeqSender().next(msg, payload, nil)})
 	if err != nil {
-		log.Fatalf("failed to call UnaryRPC: %v", err)
+		return fmt.Errorf("failed to call UnaryRPC: %w", err)
 	}
 	fmt.Println(unaryResponse.GetResponse())
+	checker := newSeqChecker()
+	if err := checker.check(unaryResponse.GetMeta(), unaryResponse.GetResponse(), unaryResponse.GetPayload()); err != nil {
+		return seqError(err)
+	}
+	fmt.Printf("sequence: %s\n", checker)
+	return nil
 }
 
-func (s *StreamingClient) clientStreamRPC(client pb.StreamingServiceClient) {
+func (s *StreamingClient) clientStreamRPC(client pb.StreamingServiceClient) error {
 	// Client Stream RPC
 	ctx := metadata.NewOutgoingContext(context.Background(), uniformHeader("clientStream"))
 	clientStream, err := client.ClientStreamRPC(ctx)
 	if err != nil {
-		log.Fatalf("failed to call ClientStreamRPC: %v", err)
+		return fmt.Errorf("failed to call ClientStreamRPC: %w", err)
 	}
+	sender := newSeqSender()
 	for i := 0; i < 3; i++ {
-		if err := clientStream.Send(&pb.ClientStreamRequest{Message: fmt.Sprintf("Client Stream Message %d", i)}); err != nil {
-			log.Fatalf("failed to send ClientStreamRequest: %v", err)
+		msg := fmt.Sprintf("Client Stream Message %d", i)
+		payload := payloads.next()
+		if err := clientStream.Send(&pb.ClientStreamRequest{Message: msg, Payload: payload, Meta: sender.next(msg, payload, nil)}); err != nil {
+			return fmt.Errorf("failed to send ClientStreamRequest: %w", err)
 		}
 		time.Sleep(time.Duration(ServerDelay) * time.Millisecond)
 	}
 	clientStreamResponse, err := clientStream.CloseAndRecv()
 	if err != nil {
-		log.Fatalf("failed to receive ClientStreamResponse: %v", err)
+		return fmt.Errorf("failed to receive ClientStreamResponse: %w", err)
 	}
 	fmt.Println(clientStreamResponse.GetResponse())
+	checker := newSeqChecker()
+	if err := checker.check(clientStreamResponse.GetMeta(), clientStreamResponse.GetResponse(), clientStreamResponse.GetPayload()); err != nil {
+		return seqError(err)
+	}
+	fmt.Printf("sequence: %s\n", checker)
+	return nil
 }
 
-func (s *StreamingClient) clientRepeatedStream() {
-	s.recvChan <- "Hello, 1"
-	time.Sleep(100 * time.Millisecond)
-	s.recvChan <- "Hello, 2"
-	time.Sleep(100 * time.Millisecond)
-	s.recvChan <- "Hello, 3"
+func (s *StreamingClient) clientRepeatedStream() error {
+	for i := 1; i <= 3; i++ {
+		if i > 1 {
+			time.Sleep(100 * time.Millisecond)
+		}
+		if err := s.sendOnClientStream(fmt.Sprintf("Hello, %d", i)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-func (s *StreamingClient) serverStreamRPC(client pb.StreamingServiceClient) {
+// sendOnClientStream hands text to the long-lived client stream and waits for its send result
+func (s *StreamingClient) sendOnClientStream(text string) error {
+	m := clientStreamMessage{text: text, sent: make(chan error, 1)}
+	timeout := time.After(clientStreamTimeout)
+	select {
+	case s.recvChan <- m:
+	case <-timeout:
+		return fmt.Errorf("client stream not ready after %v, %q not sent", clientStreamTimeout, text)
+	}
+	select {
+	case err := <-m.sent:
+		return err
+	case <-timeout:
+		return fmt.Errorf("client stream did not send %q within %v", text, clientStreamTimeout)
+	}
+}
+
+func (s *StreamingClient) serverStreamRPC(client pb.StreamingServiceClient) error {
 	// Server Stream RPC
 	ctx := metadata.NewOutgoingContext(context.Background(), uniformHeader("serverStream"))
-	serverStream, err := client.ServerStreamRPC(ctx, &pb.ServerStreamRequest{Message: "Hello, Server Stream RPC!"})
+	msg := "Hello, Server Stream RPC!"
+	payload := payloads.next()
+	serverStream, err := client.ServerStreamRPC(ctx, &pb.ServerStreamRequest{Message: msg, Payload: payload, Meta: newSeqSender().next(msg, payload, nil)})
 	if err != nil {
-		log.Fatalf("failed to call ServerStreamRPC: %v", err)
+		return fmt.Errorf("failed to call ServerStreamRPC: %w", err)
 	}
+	checker := newSeqChecker()
 	for {
 		resp, err := serverStream.Recv()
 		if err == io.EOF {
-			break
+			fmt.Printf("sequence: %s\n", checker)
+			return nil
 		}
 		if err != nil {
-			log.Fatalf("failed to receive ServerStreamResponse: %v", err)
+			return fmt.Errorf("failed to receive ServerStreamResponse: %w", err)
 		}
 		fmt.Println(resp.GetResponse())
+		if err := checker.check(resp.GetMeta(), resp.GetResponse(), resp.GetPayload()); err != nil {
+			return seqError(err)
+		}
 	}
 }
 
-func (s *StreamingClient) bidirectionalStreamRPC(client pb.StreamingServiceClient) {
+func (s *StreamingClient) bidirectionalStreamRPC(client pb.StreamingServiceClient) error {
 	fmt.Println("Starting Bidirectional Stream RPC...")
 	// Bidirectional Stream RPC
-	ctx := metadata.NewOutgoingContext(context.Background(), uniformHeader("bidirectionalStream"))
+	ctx, cancel := context.WithCancel(metadata.NewOutgoingContext(context.Background(), uniformHeader("bidirectionalStream")))
+	defer cancel()
 	bidirectionalStream, err := client.BidirectionalStreamRPC(ctx)
 	if err != nil {
-		log.Fatalf("failed to call BidirectionalStreamRPC: %v", err)
+		return fmt.Errorf("failed to call BidirectionalStreamRPC: %w", err)
 	}
+	sendErr := make(chan error, 1)
+	sender, checker := newSeqSender(), newSeqChecker()
 	go func() {
+		defer close(sendErr)
 		for i := 0; i < 3; i++ {
-			if err := bidirectionalStream.Send(&pb.BidirectionalStreamRequest{Message: fmt.Sprintf("Bidirectional Stream Message %d", i)}); err != nil {
-				log.Fatalf("failed to send BidirectionalStreamRequest: %v", err)
+			msg := fmt.Sprintf("Bidirectional Stream Message %d", i)
+			payload := payloads.next()
+			if err := bidirectionalStream.Send(&pb.BidirectionalStreamRequest{Message: msg, Payload: payload, Meta: sender.next(msg, payload, nil)}); err != nil {
+				sendErr <- fmt.Errorf("failed to send BidirectionalStreamRequest: %w", err)
+				return
 			}
 			time.Sleep(1 * time.Second)
 		}
 		if err := bidirectionalStream.CloseSend(); err != nil {
-			log.Fatalf("failed to close send stream: %v", err)
+			sendErr <- fmt.Errorf("failed to close send stream: %w", err)
 		}
 	}()
 	for {
@@ -199,9 +459,13 @@ func (s *StreamingClient) bidirectionalStreamRPC(client pb.StreamingServiceClien
 			break
 		}
 		if err != nil {
-			log.Fatalf("failed to receive BidirectionalStreamResponse: %v", err)
+			return fmt.Errorf("failed to receive BidirectionalStreamResponse: %w", err)
 		}
 		fmt.Println(resp.GetResponse())
+		if err := checker.check(resp.GetMeta(), resp.GetResponse(), resp.GetPayload()); err != nil {
+			return seqError(err)
+		}
 	}
-
+	fmt.Printf("sequence: %s\n", checker)
+	return <-sendErr
 }
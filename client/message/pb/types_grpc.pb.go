@@ -0,0 +1,322 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.12.4
+// source: types.proto
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	TypesService_Echo_FullMethodName             = "/message.TypesService/Echo"
+	TypesService_EchoServerStream_FullMethodName = "/message.TypesService/EchoServerStream"
+	TypesService_EchoClientStream_FullMethodName = "/message.TypesService/EchoClientStream"
+	TypesService_EchoBidiStream_FullMethodName   = "/message.TypesService/EchoBidiStream"
+)
+
+// TypesServiceClient is the client API for TypesService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type TypesServiceClient interface {
+	// Unary echo
+	Echo(ctx context.Context, in *AllTypes, opts ...grpc.CallOption) (*AllTypes, error)
+	// Sends count variations of the template
+	EchoServerStream(ctx context.Context, in *TypesStreamRequest, opts ...grpc.CallOption) (TypesService_EchoServerStreamClient, error)
+	// Returns the number of messages received and the last one
+	EchoClientStream(ctx context.Context, opts ...grpc.CallOption) (TypesService_EchoClientStreamClient, error)
+	// Echoes every message
+	EchoBidiStream(ctx context.Context, opts ...grpc.CallOption) (TypesService_EchoBidiStreamClient, error)
+}
+
+type typesServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewTypesServiceClient(cc grpc.ClientConnInterface) TypesServiceClient {
+	return &typesServiceClient{cc}
+}
+
+func (c *typesServiceClient) Echo(ctx context.Context, in *AllTypes, opts ...grpc.CallOption) (*AllTypes, error) {
+	out := new(AllTypes)
+	err := c.cc.Invoke(ctx, TypesService_Echo_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *typesServiceClient) EchoServerStream(ctx context.Context, in *TypesStreamRequest, opts ...grpc.CallOption) (TypesService_EchoServerStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TypesService_ServiceDesc.Streams[0], TypesService_EchoServerStream_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &typesServiceEchoServerStreamClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TypesService_EchoServerStreamClient interface {
+	Recv() (*AllTypes, error)
+	grpc.ClientStream
+}
+
+type typesServiceEchoServerStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *typesServiceEchoServerStreamClient) Recv() (*AllTypes, error) {
+	m := new(AllTypes)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *typesServiceClient) EchoClientStream(ctx context.Context, opts ...grpc.CallOption) (TypesService_EchoClientStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TypesService_ServiceDesc.Streams[1], TypesService_EchoClientStream_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &typesServiceEchoClientStreamClient{stream}
+	return x, nil
+}
+
+type TypesService_EchoClientStreamClient interface {
+	Send(*AllTypes) error
+	CloseAndRecv() (*TypesSummary, error)
+	grpc.ClientStream
+}
+
+type typesServiceEchoClientStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *typesServiceEchoClientStreamClient) Send(m *AllTypes) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *typesServiceEchoClientStreamClient) CloseAndRecv() (*TypesSummary, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(TypesSummary)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *typesServiceClient) EchoBidiStream(ctx context.Context, opts ...grpc.CallOption) (TypesService_EchoBidiStreamClient, error) {
+	stream, err := c.cc.NewStream(ctx, &TypesService_ServiceDesc.Streams[2], TypesService_EchoBidiStream_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &typesServiceEchoBidiStreamClient{stream}
+	return x, nil
+}
+
+type TypesService_EchoBidiStreamClient interface {
+	Send(*AllTypes) error
+	Recv() (*AllTypes, error)
+	grpc.ClientStream
+}
+
+type typesServiceEchoBidiStreamClient struct {
+	grpc.ClientStream
+}
+
+func (x *typesServiceEchoBidiStreamClient) Send(m *AllTypes) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *typesServiceEchoBidiStreamClient) Recv() (*AllTypes, error) {
+	m := new(AllTypes)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// TypesServiceServer is the server API for TypesService service.
+// All implementations must embed UnimplementedTypesServiceServer
+// for forward compatibility
+type TypesServiceServer interface {
+	// Unary echo
+	Echo(context.Context, *AllTypes) (*AllTypes, error)
+	// Sends count variations of the template
+	EchoServerStream(*TypesStreamRequest, TypesService_EchoServerStreamServer) error
+	// Returns the number of messages received and the last one
+	EchoClientStream(TypesService_EchoClientStreamServer) error
+	// Echoes every message
+	EchoBidiStream(TypesService_EchoBidiStreamServer) error
+	mustEmbedUnimplementedTypesServiceServer()
+}
+
+// UnimplementedTypesServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedTypesServiceServer struct {
+}
+
+func (UnimplementedTypesServiceServer) Echo(context.Context, *AllTypes) (*AllTypes, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Echo not implemented")
+}
+func (UnimplementedTypesServiceServer) EchoServerStream(*TypesStreamRequest, TypesService_EchoServerStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method EchoServerStream not implemented")
+}
+func (UnimplementedTypesServiceServer) EchoClientStream(TypesService_EchoClientStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method EchoClientStream not implemented")
+}
+func (UnimplementedTypesServiceServer) EchoBidiStream(TypesService_EchoBidiStreamServer) error {
+	return status.Errorf(codes.Unimplemented, "method EchoBidiStream not implemented")
+}
+func (UnimplementedTypesServiceServer) mustEmbedUnimplementedTypesServiceServer() {}
+
+// UnsafeTypesServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to TypesServiceServer will
+// result in compilation errors.
+type UnsafeTypesServiceServer interface {
+	mustEmbedUnimplementedTypesServiceServer()
+}
+
+func RegisterTypesServiceServer(s grpc.ServiceRegistrar, srv TypesServiceServer) {
+	s.RegisterService(&TypesService_ServiceDesc, srv)
+}
+
+func _TypesService_Echo_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AllTypes)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(TypesServiceServer).Echo(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: TypesService_Echo_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(TypesServiceServer).Echo(ctx, req.(*AllTypes))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _TypesService_EchoServerStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TypesStreamRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TypesServiceServer).EchoServerStream(m, &typesServiceEchoServerStreamServer{stream})
+}
+
+type TypesService_EchoServerStreamServer interface {
+	Send(*AllTypes) error
+	grpc.ServerStream
+}
+
+type typesServiceEchoServerStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *typesServiceEchoServerStreamServer) Send(m *AllTypes) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TypesService_EchoClientStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TypesServiceServer).EchoClientStream(&typesServiceEchoClientStreamServer{stream})
+}
+
+type TypesService_EchoClientStreamServer interface {
+	SendAndClose(*TypesSummary) error
+	Recv() (*AllTypes, error)
+	grpc.ServerStream
+}
+
+type typesServiceEchoClientStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *typesServiceEchoClientStreamServer) SendAndClose(m *TypesSummary) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *typesServiceEchoClientStreamServer) Recv() (*AllTypes, error) {
+	m := new(AllTypes)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _TypesService_EchoBidiStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TypesServiceServer).EchoBidiStream(&typesServiceEchoBidiStreamServer{stream})
+}
+
+type TypesService_EchoBidiStreamServer interface {
+	Send(*AllTypes) error
+	Recv() (*AllTypes, error)
+	grpc.ServerStream
+}
+
+type typesServiceEchoBidiStreamServer struct {
+	grpc.ServerStream
+}
+
+func (x *typesServiceEchoBidiStreamServer) Send(m *AllTypes) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *typesServiceEchoBidiStreamServer) Recv() (*AllTypes, error) {
+	m := new(AllTypes)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// TypesService_ServiceDesc is the grpc.ServiceDesc for TypesService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var TypesService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "message.TypesService",
+	HandlerType: (*TypesServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Echo",
+			Handler:    _TypesService_Echo_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "EchoServerStream",
+			Handler:       _TypesService_EchoServerStream_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "EchoClientStream",
+			Handler:       _TypesService_EchoClientStream_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "EchoBidiStream",
+			Handler:       _TypesService_EchoBidiStream_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
+	Metadata: "types.proto",
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// byteSize is a size flag accepting a K, M or G suffix, all powers of 1024, e.g. 512, 64K, 16MiB
+type byteSize int
+
+func (s *byteSize) String() string {
+	return strconv.Itoa(int(*s))
+}
+
+func (s *byteSize) Set(value string) error {
+	v := strings.TrimSuffix(strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(value)), "B"), "I")
+	unit := 1
+	switch {
+	case strings.HasSuffix(v, "K"):
+		unit = 1 << 10
+	case strings.HasSuffix(v, "M"):
+		unit = 1 << 20
+	case strings.HasSuffix(v, "G"):
+		unit = 1 << 30
+	}
+	if unit > 1 {
+		v = v[:len(v)-1]
+	}
+	n, err := strconv.ParseFloat(v, 64)
+	// ParseFloat also reads NaN, Inf and exponents, the size has to fit an int64
+	if err != nil || math.IsNaN(n) || n < 0 || n*float64(unit) >= math.MaxInt64 {
+		return fmt.Errorf("invalid size %q", value)
+	}
+	*s = byteSize(n * float64(unit))
+	return nil
+}
+
+// payloadConfig is filled by the -payload-* flags
+type payloadConfig struct {
+	// Distribution of the sizes: fixed, uniform, normal or pareto
+	Distribution string
+	// Size is the fixed size, the mean of normal sizes and the minimum of pareto sizes
+	Size byteSize
+	// Min and Max bound every size, uniform sizes are drawn between them
+	Min byteSize
+	Max byteSize
+	// StdDev is the standard deviation of normal sizes
+	StdDev byteSize
+	// Alpha is the shape of pareto sizes, the lower the longer the tail
+	Alpha float64
+	// Content is random, text (compressible) or zeros
+	Content string
+}
+
+// payloadGenerator builds the payload of every request, it is safe for concurrent use
+type payloadGenerator struct {
+	cfg payloadConfig
+
+	// random fills the random payloads, a rand.Rand is not safe for concurrent use
+	mu     sync.Mutex
+	random *rand.Rand
+}
+
+// payloads is set by the flags and used by every mode, it sends no payload by default
+var payloads = &payloadGenerator{cfg: payloadConfig{Distribution: "fixed", Content: "random"}, random: newPayloadRand()}
+
+// newPayloadRand is the source of the random payload bytes, which need no crypto/rand
+func newPayloadRand() *rand.Rand {
+	return rand.New(rand.NewSource(time.Now().UnixNano()))
+}
+
+func newPayloadGenerator(cfg payloadConfig) (*payloadGenerator, error) {
+	switch cfg.Distribution {
+	case "fixed", "uniform", "normal", "pareto":
+	default:
+		return nil, fmt.Errorf("unknown payload distribution: %s", cfg.Distribution)
+	}
+	switch cfg.Content {
+	case "random", "text", "zeros":
+	default:
+		return nil, fmt.Errorf("unknown payload content: %s", cfg.Content)
+	}
+	if cfg.Max > 0 && cfg.Min > cfg.Max {
+		return nil, fmt.Errorf("payload min %d is above max %d", cfg.Min, cfg.Max)
+	}
+	if cfg.Distribution == "uniform" && cfg.Max == 0 {
+		return nil, fmt.Errorf("uniform payload sizes need a max")
+	}
+	if cfg.Distribution == "pareto" && cfg.Alpha <= 0 {
+		return nil, fmt.Errorf("pareto payload alpha must be positive")
+	}
+	return &payloadGenerator{cfg: cfg, random: newPayloadRand()}, nil
+}
+
+// size draws the size of the next payload
+func (g *payloadGenerator) size() int {
+	cfg := g.cfg
+	var size float64
+	switch cfg.Distribution {
+	case "fixed":
+		size = float64(cfg.Size)
+	case "uniform":
+		size = float64(cfg.Min) + rand.Float64()*float64(cfg.Max-cfg.Min+1)
+	case "normal":
+		size = float64(cfg.Size) + rand.NormFloat64()*float64(cfg.StdDev)
+	case "pareto":
+		// inverse transform sampling, scale is the smallest possible size
+		scale := math.Max(float64(cfg.Size), 1)
+		size = scale / math.Pow(1-rand.Float64(), 1/cfg.Alpha)
+	}
+	size = math.Max(size, float64(cfg.Min))
+	if cfg.Max > 0 {
+		size = math.Min(size, float64(cfg.Max))
+	}
+	return int(size)
+}
+
+var loremWords = strings.Fields("lorem ipsum dolor sit amet consectetur adipiscing elit sed do eiusmod tempor incididunt ut labore et dolore magna aliqua")
+
+// next returns a new payload, nil when its size is 0
+func (g *payloadGenerator) next() []byte {
+	size := g.size()
+	if size <= 0 {
+		return nil
+	}
+	payload := make([]byte, size)
+	switch g.cfg.Content {
+	case "random":
+		g.mu.Lock()
+		g.random.Read(payload)
+		g.mu.Unlock()
+	case "text":
+		for i := 0; i < size; {
+			i += copy(payload[i:], loremWords[rand.Intn(len(loremWords))])
+			if i < size {
+				payload[i] = ' '
+				i++
+			}
+		}
+	}
+	return payload
+}
@@ -0,0 +1,109 @@
+package main
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestByteSizeSet(t *testing.T) {
+	tests := []struct {
+		value string
+		want  byteSize
+	}{
+		{"0", 0},
+		{"512", 512},
+		{"1B", 1},
+		{"64K", 64 << 10},
+		{"64k", 64 << 10},
+		{"1.5K", 1536},
+		{"16M", 16 << 20},
+		{"16MiB", 16 << 20},
+		{"16MB", 16 << 20},
+		{" 4mb ", 4 << 20},
+		{"2G", 2 << 30},
+	}
+	for _, tt := range tests {
+		var s byteSize
+		if err := s.Set(tt.value); err != nil {
+			t.Errorf("Set(%q): %v", tt.value, err)
+			continue
+		}
+		if s != tt.want {
+			t.Errorf("Set(%q) = %d, want %d", tt.value, s, tt.want)
+		}
+	}
+}
+
+func TestByteSizeSetInvalid(t *testing.T) {
+	for _, value := range []string{"", "K", "-1", "-1K", "abc", "10X", "1KK", "NaN", "Inf", "1e30"} {
+		var s byteSize
+		if err := s.Set(value); err == nil {
+			t.Errorf("Set(%q) = %d, want an error", value, s)
+		}
+	}
+}
+
+func TestNewPayloadGeneratorInvalid(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  payloadConfig
+	}{
+		{"unknown distribution", payloadConfig{Distribution: "poisson", Content: "random"}},
+		{"unknown content", payloadConfig{Distribution: "fixed", Content: "ones"}},
+		{"min above max", payloadConfig{Distribution: "fixed", Content: "random", Min: 2 << 10, Max: 1 << 10}},
+		{"uniform without max", payloadConfig{Distribution: "uniform", Content: "random"}},
+		{"pareto without alpha", payloadConfig{Distribution: "pareto", Content: "random", Size: 1 << 10}},
+	}
+	for _, tt := range tests {
+		if _, err := newPayloadGenerator(tt.cfg); err == nil {
+			t.Errorf("%s: newPayloadGenerator(%+v) succeeded, want an error", tt.name, tt.cfg)
+		}
+	}
+}
+
+func TestPayloadGeneratorSizes(t *testing.T) {
+	tests := []struct {
+		name     string
+		cfg      payloadConfig
+		min, max int
+	}{
+		{"fixed", payloadConfig{Distribution: "fixed", Size: 100}, 100, 100},
+		{"uniform", payloadConfig{Distribution: "uniform", Min: 10, Max: 20}, 10, 20},
+		{"normal bounded", payloadConfig{Distribution: "normal", Size: 100, StdDev: 1000, Min: 50, Max: 150}, 50, 150},
+		{"pareto above its scale", payloadConfig{Distribution: "pareto", Size: 64, Alpha: 1.5, Max: 1 << 20}, 64, 1 << 20},
+	}
+	for _, tt := range tests {
+		tt.cfg.Content = "zeros"
+		g, err := newPayloadGenerator(tt.cfg)
+		if err != nil {
+			t.Fatalf("%s: %v", tt.name, err)
+		}
+		for i := 0; i < 1000; i++ {
+			if size := len(g.next()); size < tt.min || size > tt.max {
+				t.Fatalf("%s: payload of %d bytes, want %d to %d", tt.name, size, tt.min, tt.max)
+			}
+		}
+	}
+}
+
+func TestPayloadGeneratorContent(t *testing.T) {
+	for _, content := range []string{"random", "text", "zeros"} {
+		g, err := newPayloadGenerator(payloadConfig{Distribution: "fixed", Size: 4 << 10, Content: content})
+		if err != nil {
+			t.Fatal(err)
+		}
+		payload := g.next()
+		if len(payload) != 4<<10 {
+			t.Fatalf("%s payload of %d bytes, want %d", content, len(payload), 4<<10)
+		}
+		zeros := bytes.Count(payload, []byte{0})
+		if (content == "zeros") != (zeros == len(payload)) {
+			t.Errorf("%s payload has %d zero bytes out of %d", content, zeros, len(payload))
+		}
+	}
+	// no payload at all rather than an empty one
+	g, _ := newPayloadGenerator(payloadConfig{Distribution: "fixed", Content: "random"})
+	if payload := g.next(); payload != nil {
+		t.Errorf("payload of size 0 is %v, want nil", payload)
+	}
+}
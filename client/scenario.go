@@ -0,0 +1,105 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"client/message/pb"
+)
+
+// exit codes of the non-interactive modes
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitUsage   = 2
+)
+
+var scenarioNames = []string{"unaryRPC", "clientStreamRPC", "serverStreamRPC", "bidirectionalStreamRPC", "clientRepeatedStream"}
+
+func (s *StreamingClient) scenario(client pb.StreamingServiceClient, name string) (func() error, bool) {
+	switch name {
+	case "unaryRPC":
+		return func() error { return s.unaryRPC(client) }, true
+	case "clientStreamRPC":
+		return func() error { return s.clientStreamRPC(client) }, true
+	case "serverStreamRPC":
+		return func() error { return s.serverStreamRPC(client) }, true
+	case "bidirectionalStreamRPC":
+		return func() error { return s.bidirectionalStreamRPC(client) }, true
+	case "clientRepeatedStream":
+		return s.clientRepeatedStream, true
+	}
+	return nil, false
+}
+
+// runScenarios runs the given scenarios one after another, count times or until duration is reached,
+// and returns the process exit code.
+func (s *StreamingClient) runScenarios(client pb.StreamingServiceClient, scenario string, count int, interval, duration time.Duration) int {
+	var names []string
+	var calls []func() error
+	for _, name := range strings.Split(scenario, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		call, ok := s.scenario(client, name)
+		if !ok {
+			fmt.Printf("unknown scenario: %s, available: %s\n", name, strings.Join(scenarioNames, ", "))
+			return exitUsage
+		}
+		names = append(names, name)
+		calls = append(calls, call)
+	}
+	if len(calls) == 0 {
+		fmt.Println("no scenario given, use -scenario")
+		return exitUsage
+	}
+	if count <= 0 && duration <= 0 {
+		fmt.Println("-count or -duration must be set in scenario mode")
+		return exitUsage
+	}
+
+	var deadline time.Time
+	if duration > 0 {
+		deadline = time.Now().Add(duration)
+	}
+	done := func(run int) bool {
+		if count > 0 && run >= count {
+			return true
+		}
+		return !deadline.IsZero() && !time.Now().Before(deadline)
+	}
+
+	failures := 0
+	total := 0
+	run := 0
+	for ; !done(run); run++ {
+		if run > 0 && interval > 0 {
+			time.Sleep(interval)
+		}
+		for i, call := range calls {
+			total++
+			if err := call(); err != nil {
+				failures++
+				fmt.Printf("run %d, scenario %s failed: %v\n", run, names[i], err)
+			}
+		}
+	}
+	s.stopClientStream()
+
+	fmt.Printf("finished %d runs, %d calls, %d failed\n", run, total, failures)
+	if failures > 0 {
+		return exitFailure
+	}
+	return exitOK
+}
+
+// stopClientStream asks the long-lived client stream to exit, without blocking forever
+// when that stream is still retrying to connect.
+func (s *StreamingClient) stopClientStream() {
+	select {
+	case s.recvChan <- clientStreamMessage{}:
+	case <-time.After(time.Second):
+	}
+}
@@ -0,0 +1,335 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"client/message/pb"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"sigs.k8s.io/yaml"
+)
+
+// scenarioFile describes a traffic run, written in YAML or JSON:
+//
+//	steps:
+//	  - name: say hello
+//	    rpc: BidirectionalStreamRPC
+//	    count: 3
+//	    payload: "Hello {i}"
+//	    metadata: {tenant: demo}
+//	    delay: 100ms
+//	    deadline: 5s
+//	    expect: "Hello"
+//	    expectResponses: 3
+type scenarioFile struct {
+	Steps []scenarioStep `json:"steps"`
+}
+
+type scenarioStep struct {
+	Name string `json:"name"`
+	// RPC is one of the StreamingService methods
+	RPC string `json:"rpc"`
+	// Count is the number of messages to send, unary and server stream RPCs are called Count times
+	Count int `json:"count"`
+	// Payload is the message to send, {i} is replaced with the message index
+	Payload string `json:"payload"`
+	// Payloads are sent in turn instead of Payload when set
+	Payloads []string          `json:"payloads"`
+	Metadata map[string]string `json:"metadata"`
+	// Delay is the wait time between two messages
+	Delay duration `json:"delay"`
+	// Wait is the wait time after the step, before the next one
+	Wait     duration `json:"wait"`
+	Deadline duration `json:"deadline"`
+	// Codec is the content-subtype of the step, e.g. json, proto by default
+	Codec string `json:"codec"`
+	// Compression compresses the requests of the step, gzip, zstd, snappy or identity
+	Compression string `json:"compression"`
+	// ResponseCount, ResponsesPerRequest, ResponseInterval and PayloadSize shape the
+	// server and bidirectional stream responses, zero values keep the server defaults
+	ResponseCount       int32    `json:"responseCount"`
+	ResponsesPerRequest int32    `json:"responsesPerRequest"`
+	ResponseInterval    duration `json:"responseInterval"`
+	PayloadSize         int32    `json:"payloadSize"`
+	// Expect must be contained in every response
+	Expect          string     `json:"expect"`
+	ExpectResponses *int       `json:"expectResponses"`
+	ExpectCode      codes.Code `json:"expectCode"`
+}
+
+// duration accepts both "100ms" like strings and numbers in milliseconds
+type duration time.Duration
+
+func (d *duration) UnmarshalJSON(b []byte) error {
+	if s, err := strconv.Unquote(string(b)); err == nil {
+		v, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		*d = duration(v)
+		return nil
+	}
+	var ms float64
+	if err := json.Unmarshal(b, &ms); err != nil {
+		return fmt.Errorf("invalid duration %s", b)
+	}
+	*d = duration(time.Duration(ms * float64(time.Millisecond)))
+	return nil
+}
+
+func loadScenarioFile(path string) (*scenarioFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	// YAML is a superset of JSON, so both formats go through the same decoder
+	f := &scenarioFile{}
+	if err := yaml.UnmarshalStrict(data, f); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	for i := range f.Steps {
+		step := &f.Steps[i]
+		if step.Name == "" {
+			step.Name = fmt.Sprintf("step-%d", i+1)
+		}
+		switch step.RPC {
+		case "UnaryRPC", "ClientStreamRPC", "ServerStreamRPC", "BidirectionalStreamRPC":
+		default:
+			return nil, fmt.Errorf("%s: unknown rpc %q", step.Name, step.RPC)
+		}
+		if step.Codec != "" && encoding.GetCodec(step.Codec) == nil {
+			return nil, fmt.Errorf("%s: unknown codec %q", step.Name, step.Codec)
+		}
+		if step.Compression != "" && step.Compression != encoding.Identity && encoding.GetCompressor(step.Compression) == nil {
+			return nil, fmt.Errorf("%s: unknown compressor %q", step.Name, step.Compression)
+		}
+		if step.Count <= 0 {
+			step.Count = len(step.Payloads)
+		}
+		if step.Count <= 0 {
+			step.Count = 1
+		}
+	}
+	return f, nil
+}
+
+func (step *scenarioStep) message(i int) string {
+	if len(step.Payloads) > 0 {
+		return step.Payloads[i%len(step.Payloads)]
+	}
+	payload := step.Payload
+	if payload == "" {
+		payload = "Scenario Message {i}"
+	}
+	return strings.ReplaceAll(payload, "{i}", strconv.Itoa(i))
+}
+
+// intervalMs converts ResponseInterval to the request field, where negative means no wait
+func (step *scenarioStep) intervalMs() int32 {
+	if step.ResponseInterval < 0 {
+		return -1
+	}
+	return int32(time.Duration(step.ResponseInterval) / time.Millisecond)
+}
+
+// runScenarioFile executes every step of the file in order, reports pass/fail for each
+// and returns the process exit code.
+func runScenarioFile(client pb.StreamingServiceClient, path string) int {
+	f, err := loadScenarioFile(path)
+	if err != nil {
+		fmt.Println(err)
+		return exitUsage
+	}
+	failures := 0
+	for i := range f.Steps {
+		step := &f.Steps[i]
+		start := time.Now()
+		responses, err := step.run(client)
+		if reason := step.verify(responses, err); reason != "" {
+			failures++
+			fmt.Printf("FAIL %s (%s): %s\n", step.Name, step.RPC, reason)
+		} else {
+			fmt.Printf("PASS %s (%s): %d responses in %v\n", step.Name, step.RPC, len(responses), time.Since(start))
+		}
+		time.Sleep(time.Duration(step.Wait))
+	}
+	fmt.Printf("%d steps, %d passed, %d failed\n", len(f.Steps), len(f.Steps)-failures, failures)
+	if failures > 0 {
+		return exitFailure
+	}
+	return exitOK
+}
+
+func (step *scenarioStep) verify(responses []string, err error) string {
+	if code := status.Code(err); code != step.ExpectCode {
+		if err != nil {
+			return fmt.Sprintf("expected code %v, got %v", step.ExpectCode, err)
+		}
+		return fmt.Sprintf("expected code %v, got %v", step.ExpectCode, code)
+	}
+	if step.ExpectResponses != nil && len(responses) != *step.ExpectResponses {
+		return fmt.Sprintf("expected %d responses, got %d", *step.ExpectResponses, len(responses))
+	}
+	for _, resp := range responses {
+		if !strings.Contains(resp, step.Expect) {
+			return fmt.Sprintf("response %q does not contain %q", resp, step.Expect)
+		}
+	}
+	return ""
+}
+
+func (step *scenarioStep) context() (context.Context, context.CancelFunc) {
+	md := uniformHeader(step.Name)
+	for k, v := range step.Metadata {
+		md.Set(k, v)
+	}
+	ctx := metadata.NewOutgoingContext(context.Background(), md)
+	if step.Deadline > 0 {
+		return context.WithTimeout(ctx, time.Duration(step.Deadline))
+	}
+	return context.WithCancel(ctx)
+}
+
+func (step *scenarioStep) callOptions() []grpc.CallOption {
+	var opts []grpc.CallOption
+	if step.Codec != "" {
+		opts = append(opts, grpc.CallContentSubtype(step.Codec))
+	}
+	if step.Compression != "" {
+		opts = append(opts, grpc.UseCompressor(step.Compression))
+	}
+	return opts
+}
+
+func (step *scenarioStep) run(client pb.StreamingServiceClient) ([]string, error) {
+	ctx, cancel := step.context()
+	defer cancel()
+	opts := step.callOptions()
+	var responses []string
+	switch step.RPC {
+	case "UnaryRPC":
+		for i := 0; i < step.Count; i++ {
+			if i > 0 {
+				time.Sleep(time.Duration(step.Delay))
+			}
+			msg := step.message(i)
+			payload := payloads.next()
+			resp, err := client.UnaryRPC(ctx, &pb.UnaryRequest{Message: msg, Payload: payload, Meta: newSeqSender().next(msg, payload, nil)}, opts...)
+			if err != nil {
+				return responses, err
+			}
+			if err := newSeqChecker().check(resp.GetMeta(), resp.GetResponse(), resp.GetPayload()); err != nil {
+				return responses, seqError(err)
+			}
+			responses = append(responses, resp.GetResponse())
+		}
+	case "ClientStreamRPC":
+		stream, err := client.ClientStreamRPC(ctx, opts...)
+		if err != nil {
+			return nil, err
+		}
+		sender := newSeqSender()
+		for i := 0; i < step.Count; i++ {
+			if i > 0 {
+				time.Sleep(time.Duration(step.Delay))
+			}
+			msg := step.message(i)
+			payload := payloads.next()
+			if err := stream.Send(&pb.ClientStreamRequest{Message: msg, Payload: payload, Meta: sender.next(msg, payload, nil)}); err != nil {
+				break // the real error is returned by CloseAndRecv
+			}
+		}
+		resp, err := stream.CloseAndRecv()
+		if err != nil {
+			return nil, err
+		}
+		if err := newSeqChecker().check(resp.GetMeta(), resp.GetResponse(), resp.GetPayload()); err != nil {
+			return nil, seqError(err)
+		}
+		responses = append(responses, resp.GetResponse())
+	case "ServerStreamRPC":
+		for i := 0; i < step.Count; i++ {
+			if i > 0 {
+				time.Sleep(time.Duration(step.Delay))
+			}
+			msg := step.message(i)
+			payload := payloads.next()
+			stream, err := client.ServerStreamRPC(ctx, &pb.ServerStreamRequest{
+				Message:       msg,
+				ResponseCount: step.ResponseCount,
+				IntervalMs:    step.intervalMs(),
+				PayloadSize:   step.PayloadSize,
+				Payload:       payload,
+				Meta:          newSeqSender().next(msg, payload, nil),
+			}, opts...)
+			if err != nil {
+				return responses, err
+			}
+			checker := newSeqChecker()
+			for {
+				resp, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					return responses, err
+				}
+				if err := checker.check(resp.GetMeta(), resp.GetResponse(), resp.GetPayload()); err != nil {
+					return responses, seqError(err)
+				}
+				responses = append(responses, resp.GetResponse())
+			}
+		}
+	case "BidirectionalStreamRPC":
+		stream, err := client.BidirectionalStreamRPC(ctx, opts...)
+		if err != nil {
+			return nil, err
+		}
+		sender, checker := newSeqSender(), newSeqChecker()
+		go func() {
+			for i := 0; i < step.Count; i++ {
+				if i > 0 {
+					time.Sleep(time.Duration(step.Delay))
+				}
+				msg := step.message(i)
+				payload := payloads.next()
+				if err := stream.Send(&pb.BidirectionalStreamRequest{
+					Message:             msg,
+					ResponsesPerRequest: step.ResponsesPerRequest,
+					IntervalMs:          step.intervalMs(),
+					PayloadSize:         step.PayloadSize,
+					Payload:             payload,
+					Meta:                sender.next(msg, payload, nil),
+				}); err != nil {
+					return // the real error is returned by Recv
+				}
+			}
+			stream.CloseSend()
+		}()
+		for {
+			resp, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return responses, err
+			}
+			if err := checker.check(resp.GetMeta(), resp.GetResponse(), resp.GetPayload()); err != nil {
+				return responses, seqError(err)
+			}
+			responses = append(responses, resp.GetResponse())
+		}
+	}
+	return responses, nil
+}
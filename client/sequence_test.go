@@ -0,0 +1,129 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"client/message/pb"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// seqMessage is one received message of a seqChecker test
+type seqMessage struct {
+	seq      uint64
+	streamID string
+	// tampered changes the text after the checksum, as a corruption on the way would
+	tampered bool
+	// wantErr is a substring of the anomaly reported for the message, none when empty
+	wantErr string
+}
+
+func TestSeqChecker(t *testing.T) {
+	tests := []struct {
+		name     string
+		messages []seqMessage
+		// want is the summary of the checker, without its latencies
+		want string
+	}{
+		{
+			name:     "in order",
+			messages: []seqMessage{{seq: 1}, {seq: 2}, {seq: 3}},
+			want:     "3 messages, 0 lost, 0 duplicated, 0 reordered, 0 corrupted",
+		},
+		{
+			name:     "gap",
+			messages: []seqMessage{{seq: 1}, {seq: 4, wantErr: "gap before seq 4, missing 2"}, {seq: 5}},
+			want:     "3 messages, 2 lost, 0 duplicated, 0 reordered, 0 corrupted",
+		},
+		{
+			name:     "late message is reordered, not lost",
+			messages: []seqMessage{{seq: 1}, {seq: 3, wantErr: "gap before seq 3"}, {seq: 2, wantErr: "seq 2 arrived out of order, after seq 3"}},
+			want:     "3 messages, 0 lost, 0 duplicated, 1 reordered, 0 corrupted",
+		},
+		{
+			name:     "duplicate",
+			messages: []seqMessage{{seq: 1}, {seq: 2}, {seq: 2, wantErr: "seq 2 is a duplicate"}, {seq: 1, wantErr: "seq 1 is a duplicate"}},
+			want:     "4 messages, 0 lost, 2 duplicated, 0 reordered, 0 corrupted",
+		},
+		{
+			name:     "reordered message seen twice is a duplicate",
+			messages: []seqMessage{{seq: 2, wantErr: "gap"}, {seq: 1, wantErr: "out of order"}, {seq: 1, wantErr: "duplicate"}},
+			want:     "3 messages, 0 lost, 1 duplicated, 1 reordered, 0 corrupted",
+		},
+		{
+			name:     "corrupted",
+			messages: []seqMessage{{seq: 1}, {seq: 2, tampered: true, wantErr: "seq 2 is corrupted"}},
+			want:     "2 messages, 0 lost, 0 duplicated, 0 reordered, 1 corrupted",
+		},
+		{
+			name:     "corrupted message is not sequenced",
+			messages: []seqMessage{{seq: 1, tampered: true, wantErr: "corrupted"}, {seq: 1}},
+			want:     "2 messages, 0 lost, 0 duplicated, 0 reordered, 1 corrupted",
+		},
+		{
+			name:     "stream id changing midway",
+			messages: []seqMessage{{seq: 1}, {seq: 2, streamID: "other", wantErr: "seq 2 belongs to stream other, expected stream"}, {seq: 2}},
+			want:     "3 messages, 0 lost, 0 duplicated, 0 reordered, 0 corrupted, 1 from another stream",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newSeqChecker()
+			for i, m := range tt.messages {
+				streamID := m.streamID
+				if streamID == "" {
+					streamID = "stream"
+				}
+				text := "hello"
+				meta := &pb.MessageMeta{StreamId: streamID, Seq: m.seq, Checksum: checksum(text, []byte{1, 2, 3})}
+				if m.tampered {
+					text += "!"
+				}
+				err := c.check(meta, text, []byte{1, 2, 3})
+				switch {
+				case m.wantErr == "" && err != nil:
+					t.Errorf("message %d: unexpected error %v", i, err)
+				case m.wantErr != "" && (err == nil || !strings.Contains(err.Error(), m.wantErr)):
+					t.Errorf("message %d: error %v, want %q", i, err, m.wantErr)
+				}
+			}
+			if got := c.String(); got != tt.want {
+				t.Errorf("summary %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeqCheckerLatencies(t *testing.T) {
+	s := newSeqSender()
+	c := newSeqChecker()
+	if got := c.String(); got != "no sequenced message" {
+		t.Errorf("empty summary %q", got)
+	}
+	// meta-less messages, e.g. from the gateway, are not checked
+	if err := c.check(nil, "hello", nil); err != nil {
+		t.Errorf("nil meta: %v", err)
+	}
+	// the request answered comes from the other side of the stream
+	req := newSeqSender().next("ping", nil, nil)
+	if err := c.check(s.next("pong", []byte("payload"), req), "pong", []byte("payload")); err != nil {
+		t.Fatal(err)
+	}
+	if got := c.String(); !strings.Contains(got, "1 messages") || !strings.Contains(got, "one-way avg") || !strings.Contains(got, "rtt avg") {
+		t.Errorf("summary %q lacks the message or its latencies", got)
+	}
+}
+
+func TestSeqError(t *testing.T) {
+	if err := seqError(nil); err != nil {
+		t.Errorf("seqError(nil) = %v", err)
+	}
+	c := newSeqChecker()
+	c.check(&pb.MessageMeta{StreamId: "stream", Seq: 1, Checksum: checksum("hello", nil)}, "hello", nil)
+	err := seqError(c.check(&pb.MessageMeta{StreamId: "stream", Seq: 3, Checksum: checksum("hello", nil)}, "hello", nil))
+	if status.Code(err) != codes.DataLoss || !strings.Contains(err.Error(), "gap before seq 3") {
+		t.Errorf("seqError = %v, want a DataLoss gap", err)
+	}
+}
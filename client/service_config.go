@@ -0,0 +1,379 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	_ "google.golang.org/grpc/balancer/weightedroundrobin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// serviceConfig is filled by the client flags, it builds the default service config of the channel
+type serviceConfig struct {
+	// File is a JSON service config used as is, instead of the policy flags
+	File string
+	// LB is the load balancing policy: pick_first, round_robin or weighted_round_robin
+	LB string
+	// Methods are the comma separated services or service/method the policies apply to
+	Methods string
+
+	RetryMaxAttempts       int
+	RetryInitialBackoff    time.Duration
+	RetryMaxBackoff        time.Duration
+	RetryBackoffMultiplier float64
+	RetryCodes             string
+
+	HedgingMaxAttempts int
+	HedgingDelay       time.Duration
+	HedgingCodes       string
+
+	// LogAttempts prints every attempt of every call with its status
+	LogAttempts bool
+}
+
+// The service config as grpc reads it, see https://github.com/grpc/grpc/blob/master/doc/service_config.md
+type serviceConfigJSON struct {
+	LoadBalancingConfig []map[string]any   `json:"loadBalancingConfig,omitempty"`
+	MethodConfig        []methodConfigJSON `json:"methodConfig,omitempty"`
+}
+
+type methodConfigJSON struct {
+	Name          []methodNameJSON   `json:"name"`
+	RetryPolicy   *retryPolicyJSON   `json:"retryPolicy,omitempty"`
+	HedgingPolicy *hedgingPolicyJSON `json:"hedgingPolicy,omitempty"`
+}
+
+type methodNameJSON struct {
+	Service string `json:"service,omitempty"`
+	Method  string `json:"method,omitempty"`
+}
+
+type retryPolicyJSON struct {
+	MaxAttempts          int      `json:"maxAttempts"`
+	InitialBackoff       string   `json:"initialBackoff"`
+	MaxBackoff           string   `json:"maxBackoff"`
+	BackoffMultiplier    float64  `json:"backoffMultiplier"`
+	RetryableStatusCodes []string `json:"retryableStatusCodes"`
+}
+
+type hedgingPolicyJSON struct {
+	MaxAttempts         int      `json:"maxAttempts"`
+	HedgingDelay        string   `json:"hedgingDelay,omitempty"`
+	NonFatalStatusCodes []string `json:"nonFatalStatusCodes,omitempty"`
+}
+
+// configDuration formats d as the service config does, seconds with an s suffix
+func configDuration(d time.Duration) string {
+	return strconv.FormatFloat(d.Seconds(), 'f', -1, 64) + "s"
+}
+
+// parseCodes checks a comma separated list of status codes and returns their names
+func parseCodes(s string) ([]string, error) {
+	var names []string
+	for _, name := range strings.Split(s, ",") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		var code codes.Code
+		if err := code.UnmarshalJSON([]byte(strconv.Quote(strings.ToUpper(name)))); err != nil {
+			return nil, fmt.Errorf("unknown status code %q", name)
+		}
+		names = append(names, strings.ToUpper(name))
+	}
+	return names, nil
+}
+
+// lbConfig is the loadBalancingConfig entry of a policy name
+func lbConfig(name string) (map[string]any, error) {
+	switch name {
+	case "pick_first", "round_robin":
+		return map[string]any{name: map[string]any{}}, nil
+	case "weighted_round_robin":
+		// the weights come from the ORCA load reports of the servers started with -orca,
+		// they apply after 1s of reports instead of 10s, demos are short
+		return map[string]any{name: map[string]any{"blackoutPeriod": "1s"}}, nil
+	}
+	return nil, fmt.Errorf("unknown load balancing policy %q", name)
+}
+
+// json returns the service config, "" when there is none
+func (c serviceConfig) json() (string, error) {
+	policyFlags := c.RetryMaxAttempts > 0 || c.HedgingMaxAttempts > 0
+	if c.File != "" {
+		if policyFlags || c.LB != "" {
+			return "", fmt.Errorf("-service-config and the -lb/-retry-*/-hedging-* flags are exclusive")
+		}
+		b, err := os.ReadFile(c.File)
+		if err != nil {
+			return "", err
+		}
+		return string(b), nil
+	}
+	if !policyFlags && c.LB == "" {
+		return "", nil
+	}
+	sc := serviceConfigJSON{}
+	if c.LB != "" {
+		lb, err := lbConfig(c.LB)
+		if err != nil {
+			return "", err
+		}
+		sc.LoadBalancingConfig = []map[string]any{lb}
+	}
+	if !policyFlags {
+		b, err := json.MarshalIndent(sc, "", "  ")
+		return string(b), err
+	}
+	if c.RetryMaxAttempts > 0 && c.HedgingMaxAttempts > 0 {
+		return "", fmt.Errorf("a method has either a retry or a hedging policy, not both")
+	}
+
+	mc := methodConfigJSON{}
+	for _, m := range strings.Split(c.Methods, ",") {
+		if m = strings.Trim(strings.TrimSpace(m), "/"); m == "" {
+			continue
+		}
+		service, method, _ := strings.Cut(m, "/")
+		mc.Name = append(mc.Name, methodNameJSON{Service: service, Method: method})
+	}
+	if len(mc.Name) == 0 {
+		return "", fmt.Errorf("no method for the policies")
+	}
+	if c.RetryMaxAttempts > 0 {
+		retryable, err := parseCodes(c.RetryCodes)
+		if err != nil {
+			return "", fmt.Errorf("invalid -retry-codes: %v", err)
+		}
+		mc.RetryPolicy = &retryPolicyJSON{
+			MaxAttempts:          c.RetryMaxAttempts,
+			InitialBackoff:       configDuration(c.RetryInitialBackoff),
+			MaxBackoff:           configDuration(c.RetryMaxBackoff),
+			BackoffMultiplier:    c.RetryBackoffMultiplier,
+			RetryableStatusCodes: retryable,
+		}
+	} else {
+		nonFatal, err := parseCodes(c.HedgingCodes)
+		if err != nil {
+			return "", fmt.Errorf("invalid -hedging-codes: %v", err)
+		}
+		mc.HedgingPolicy = &hedgingPolicyJSON{
+			MaxAttempts:         c.HedgingMaxAttempts,
+			HedgingDelay:        configDuration(c.HedgingDelay),
+			NonFatalStatusCodes: nonFatal,
+		}
+	}
+	sc.MethodConfig = []methodConfigJSON{mc}
+	b, err := json.MarshalIndent(sc, "", "  ")
+	return string(b), err
+}
+
+// dialOptions applies the service config, grpc validates it when the channel is created
+func (c serviceConfig) dialOptions() ([]grpc.DialOption, error) {
+	sc, err := c.json()
+	if err != nil || sc == "" {
+		return nil, err
+	}
+	hedging, err := parseHedgingPolicies(sc)
+	if err != nil {
+		return nil, err
+	}
+	fmt.Printf("service config: %s\n", sc)
+	opts := []grpc.DialOption{grpc.WithDefaultServiceConfig(sc)}
+	if len(hedging) > 0 {
+		opts = append(opts, grpc.WithChainUnaryInterceptor(hedging.unaryInterceptor))
+	}
+	if c.LogAttempts {
+		opts = append(opts, grpc.WithChainUnaryInterceptor(countAttemptsUnary), grpc.WithChainStreamInterceptor(countAttemptsStream),
+			grpc.WithStatsHandler(attemptLogger{}))
+	}
+	return opts, nil
+}
+
+// hedgingPolicy sends up to MaxAttempts copies of a call, HedgingDelay apart, and keeps the first
+// success or fatal status. grpc-go parses no hedgingPolicy, so it is run by an interceptor,
+// for unary calls only, as streams would have to replay their messages.
+type hedgingPolicy struct {
+	MaxAttempts int
+	Delay       time.Duration
+	NonFatal    map[codes.Code]bool
+}
+
+// hedgingPolicies are keyed by /service/method, or /service/ for a whole service
+type hedgingPolicies map[string]hedgingPolicy
+
+func parseHedgingPolicies(sc string) (hedgingPolicies, error) {
+	var parsed serviceConfigJSON
+	if err := json.Unmarshal([]byte(sc), &parsed); err != nil {
+		return nil, fmt.Errorf("invalid service config: %v", err)
+	}
+	policies := hedgingPolicies{}
+	for _, mc := range parsed.MethodConfig {
+		if mc.HedgingPolicy == nil {
+			continue
+		}
+		if mc.RetryPolicy != nil {
+			return nil, fmt.Errorf("invalid service config: a method config has both a retry and a hedging policy")
+		}
+		p := hedgingPolicy{MaxAttempts: mc.HedgingPolicy.MaxAttempts, NonFatal: map[codes.Code]bool{}}
+		if p.MaxAttempts < 2 {
+			return nil, fmt.Errorf("invalid service config: hedging maxAttempts must be at least 2")
+		}
+		// grpc caps the attempts at 5 for retries, hedging follows
+		p.MaxAttempts = min(p.MaxAttempts, 5)
+		if d := mc.HedgingPolicy.HedgingDelay; d != "" {
+			delay, err := time.ParseDuration(d)
+			if err != nil {
+				return nil, fmt.Errorf("invalid service config: hedgingDelay %q", d)
+			}
+			p.Delay = delay
+		}
+		for _, name := range mc.HedgingPolicy.NonFatalStatusCodes {
+			var code codes.Code
+			if err := code.UnmarshalJSON([]byte(strconv.Quote(name))); err != nil {
+				return nil, fmt.Errorf("invalid service config: unknown status code %q", name)
+			}
+			p.NonFatal[code] = true
+		}
+		for _, name := range mc.Name {
+			policies["/"+name.Service+"/"+name.Method] = p
+		}
+	}
+	return policies, nil
+}
+
+func (p hedgingPolicies) unaryInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	policy, ok := p[method]
+	if !ok {
+		policy, ok = p[method[:strings.LastIndex(method, "/")+1]]
+	}
+	if !ok {
+		return invoker(ctx, method, req, reply, cc, opts...)
+	}
+	return policy.invoke(ctx, method, req, reply, cc, invoker, opts...)
+}
+
+func (p hedgingPolicy) invoke(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	// the attempts left behind are canceled once one of them decided the call
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	type result struct {
+		reply proto.Message
+		err   error
+	}
+	results := make(chan result, p.MaxAttempts)
+	sent := 0
+	send := func() {
+		sent++
+		attemptCtx := ctx
+		if sent > 1 {
+			// the header grpc sets on its own retries, so the server sees hedged attempts the same way
+			attemptCtx = metadata.AppendToOutgoingContext(ctx, "grpc-previous-rpc-attempts", strconv.Itoa(sent-1))
+		}
+		attemptReply := reply.(proto.Message).ProtoReflect().New().Interface()
+		go func() {
+			err := invoker(attemptCtx, method, req, attemptReply, cc, opts...)
+			results <- result{attemptReply, err}
+		}()
+	}
+
+	send()
+	timer := time.NewTimer(p.Delay)
+	defer timer.Stop()
+	var lastErr error
+	for done := 0; done < sent || sent < p.MaxAttempts; {
+		select {
+		case <-timer.C:
+			if sent < p.MaxAttempts {
+				send()
+				timer.Reset(p.Delay)
+			}
+		case res := <-results:
+			done++
+			if res.err == nil {
+				proto.Merge(reply.(proto.Message), res.reply)
+				return nil
+			}
+			if !p.NonFatal[status.Code(res.err)] {
+				return res.err
+			}
+			lastErr = res.err
+			// a non-fatal status sends the next attempt at once
+			if sent < p.MaxAttempts {
+				send()
+				resetTimer(timer, p.Delay)
+			}
+		}
+	}
+	return lastErr
+}
+
+// resetTimer restarts t for d. Timer channels are buffered before go 1.23, so a tick that fired
+// meanwhile is drained first, it would send one more attempt at once.
+func resetTimer(t *time.Timer, d time.Duration) {
+	if !t.Stop() {
+		select {
+		case <-t.C:
+		default:
+		}
+	}
+	t.Reset(d)
+}
+
+// The attempts of a call are counted in its context, which every attempt context derives from.
+type attemptsKey struct{}
+
+func countAttemptsUnary(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	return invoker(context.WithValue(ctx, attemptsKey{}, new(atomic.Int32)), method, req, reply, cc, opts...)
+}
+
+func countAttemptsStream(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+	return streamer(context.WithValue(ctx, attemptsKey{}, new(atomic.Int32)), desc, cc, method, opts...)
+}
+
+// attemptLogger prints the attempts grpc makes for the retry policy, every hedged attempt
+// being a call of its own, they are logged as attempt 1 with their grpc-previous-rpc-attempts.
+type attemptLogger struct{}
+
+type attemptKey struct{}
+
+type attemptTag struct {
+	method  string
+	attempt int32
+}
+
+func (attemptLogger) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	attempts, ok := ctx.Value(attemptsKey{}).(*atomic.Int32)
+	if !ok {
+		return ctx
+	}
+	return context.WithValue(ctx, attemptKey{}, &attemptTag{method: info.FullMethodName, attempt: attempts.Add(1)})
+}
+
+func (attemptLogger) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	tag, ok := ctx.Value(attemptKey{}).(*attemptTag)
+	if !ok {
+		return
+	}
+	if end, ok := s.(*stats.End); ok {
+		previous := ""
+		if md, ok := metadata.FromOutgoingContext(ctx); ok && len(md.Get("grpc-previous-rpc-attempts")) > 0 {
+			previous = ", grpc-previous-rpc-attempts " + md.Get("grpc-previous-rpc-attempts")[0]
+		}
+		fmt.Printf("%s attempt %d%s: %v\n", tag.method, tag.attempt, previous, status.Code(end.Error))
+	}
+}
+
+func (attemptLogger) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context { return ctx }
+
+func (attemptLogger) HandleConn(context.Context, stats.ConnStats) {}
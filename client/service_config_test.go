@@ -0,0 +1,264 @@
+package main
+
+import (
+	"client/message/pb"
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const hedgedMethod = "/message.StreamingService/UnaryRPC"
+
+// fakeAttempt is how the fake server answers one attempt
+type fakeAttempt struct {
+	after time.Duration
+	code  codes.Code
+}
+
+// fakeInvoker answers the attempts of a call, told apart by their grpc-previous-rpc-attempts,
+// and records when they started and which ones were canceled
+type fakeInvoker struct {
+	attempts []fakeAttempt
+
+	mu       sync.Mutex
+	started  []time.Time
+	finished int
+	canceled []int
+}
+
+// wait waits for n attempts to be over, the attempts left behind by a call still run after it returned
+func (f *fakeInvoker) wait(t *testing.T, n int) {
+	t.Helper()
+	for deadline := time.Now().Add(time.Second); ; time.Sleep(time.Millisecond) {
+		f.mu.Lock()
+		finished := f.finished
+		f.mu.Unlock()
+		if finished >= n {
+			return
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("%d attempts over, want %d", finished, n)
+		}
+	}
+}
+
+func (f *fakeInvoker) invoke(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+	defer func() {
+		f.mu.Lock()
+		f.finished++
+		f.mu.Unlock()
+	}()
+	n := 0
+	if md, ok := metadata.FromOutgoingContext(ctx); ok {
+		if v := md.Get(previousAttemptsKey); len(v) > 0 {
+			n, _ = strconv.Atoi(v[len(v)-1])
+		}
+	}
+	f.mu.Lock()
+	f.started = append(f.started, time.Now())
+	f.mu.Unlock()
+	if n >= len(f.attempts) {
+		return fmt.Errorf("unexpected attempt %d", n+1)
+	}
+	a := f.attempts[n]
+	select {
+	case <-time.After(a.after):
+	case <-ctx.Done():
+		f.mu.Lock()
+		f.canceled = append(f.canceled, n+1)
+		f.mu.Unlock()
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	if a.code != codes.OK {
+		return status.Errorf(a.code, "attempt %d", n+1)
+	}
+	reply.(*pb.UnaryResponse).Response = fmt.Sprintf("attempt %d", n+1)
+	return nil
+}
+
+// previousAttemptsKey is the header of the hedged attempts after the first one
+const previousAttemptsKey = "grpc-previous-rpc-attempts"
+
+func hedgingConfig(maxAttempts int, delay string, nonFatal ...string) string {
+	codes := make([]string, 0, len(nonFatal))
+	for _, code := range nonFatal {
+		codes = append(codes, strconv.Quote(code))
+	}
+	return fmt.Sprintf(`{"methodConfig": [{"name": [{"service": "message.StreamingService"}], "hedgingPolicy": {"maxAttempts": %d, "hedgingDelay": %q, "nonFatalStatusCodes": [%s]}}]}`,
+		maxAttempts, delay, strings.Join(codes, ", "))
+}
+
+func TestHedgingPolicy(t *testing.T) {
+	const never = time.Hour
+	tests := []struct {
+		name         string
+		config       string
+		attempts     []fakeAttempt
+		wantCode     codes.Code
+		wantReply    string
+		wantAttempts int
+		wantCanceled []int
+	}{
+		{
+			name:         "first attempt answers before the delay",
+			config:       hedgingConfig(3, "1s", "UNAVAILABLE"),
+			attempts:     []fakeAttempt{{after: 0}},
+			wantReply:    "attempt 1",
+			wantAttempts: 1,
+		},
+		{
+			name:         "hedged attempt wins over a slow one",
+			config:       hedgingConfig(3, "0.02s", "UNAVAILABLE"),
+			attempts:     []fakeAttempt{{after: never}, {after: 0}},
+			wantReply:    "attempt 2",
+			wantAttempts: 2,
+			wantCanceled: []int{1},
+		},
+		{
+			name:         "non-fatal status sends the next attempt at once",
+			config:       hedgingConfig(3, "1h", "UNAVAILABLE"),
+			attempts:     []fakeAttempt{{code: codes.Unavailable}, {after: 0}},
+			wantReply:    "attempt 2",
+			wantAttempts: 2,
+		},
+		{
+			name:         "fatal status ends the call",
+			config:       hedgingConfig(3, "1h", "UNAVAILABLE"),
+			attempts:     []fakeAttempt{{code: codes.InvalidArgument}},
+			wantCode:     codes.InvalidArgument,
+			wantAttempts: 1,
+		},
+		{
+			name:         "fatal status cancels the pending attempts",
+			config:       hedgingConfig(3, "0.02s", "UNAVAILABLE"),
+			attempts:     []fakeAttempt{{after: never}, {code: codes.PermissionDenied}},
+			wantCode:     codes.PermissionDenied,
+			wantAttempts: 2,
+			wantCanceled: []int{1},
+		},
+		{
+			name:         "attempts stop at maxAttempts",
+			config:       hedgingConfig(3, "0.01s", "UNAVAILABLE"),
+			attempts:     []fakeAttempt{{code: codes.Unavailable}, {code: codes.Unavailable}, {code: codes.Unavailable}},
+			wantCode:     codes.Unavailable,
+			wantAttempts: 3,
+		},
+		{
+			name:   "maxAttempts is capped at 5",
+			config: hedgingConfig(10, "0s", "UNAVAILABLE"),
+			attempts: []fakeAttempt{
+				{code: codes.Unavailable}, {code: codes.Unavailable}, {code: codes.Unavailable}, {code: codes.Unavailable}, {code: codes.Unavailable},
+			},
+			wantCode:     codes.Unavailable,
+			wantAttempts: 5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policies, err := parseHedgingPolicies(tt.config)
+			if err != nil {
+				t.Fatal(err)
+			}
+			fake := &fakeInvoker{attempts: tt.attempts}
+			reply := &pb.UnaryResponse{}
+			err = policies.unaryInterceptor(context.Background(), hedgedMethod, &pb.UnaryRequest{}, reply, nil, fake.invoke)
+			fake.wait(t, tt.wantAttempts)
+			if code := status.Code(err); code != tt.wantCode {
+				t.Errorf("code = %v, want %v: %v", code, tt.wantCode, err)
+			}
+			if reply.Response != tt.wantReply {
+				t.Errorf("reply = %q, want %q", reply.Response, tt.wantReply)
+			}
+			if len(fake.started) != tt.wantAttempts {
+				t.Errorf("%d attempts, want %d", len(fake.started), tt.wantAttempts)
+			}
+			if fmt.Sprint(fake.canceled) != fmt.Sprint(tt.wantCanceled) {
+				t.Errorf("canceled attempts %v, want %v", fake.canceled, tt.wantCanceled)
+			}
+		})
+	}
+}
+
+func TestHedgingDelay(t *testing.T) {
+	const delay = 30 * time.Millisecond
+	policies, err := parseHedgingPolicies(hedgingConfig(3, delay.String(), "UNAVAILABLE"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake := &fakeInvoker{attempts: []fakeAttempt{{after: time.Hour}, {after: time.Hour}, {after: 0}}}
+	reply := &pb.UnaryResponse{}
+	if err := policies.unaryInterceptor(context.Background(), hedgedMethod, &pb.UnaryRequest{}, reply, nil, fake.invoke); err != nil {
+		t.Fatal(err)
+	}
+	fake.wait(t, 3)
+	if reply.Response != "attempt 3" {
+		t.Errorf("reply = %q, want attempt 3", reply.Response)
+	}
+	if len(fake.started) != 3 {
+		t.Fatalf("%d attempts, want 3", len(fake.started))
+	}
+	for i := 1; i < len(fake.started); i++ {
+		if gap := fake.started[i].Sub(fake.started[i-1]); gap < delay {
+			t.Errorf("attempt %d sent %v after attempt %d, want at least %v", i+1, gap, i, delay)
+		}
+	}
+}
+
+func TestHedgingPolicyCanceledCall(t *testing.T) {
+	policies, err := parseHedgingPolicies(hedgingConfig(2, "0s", "UNAVAILABLE"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake := &fakeInvoker{attempts: []fakeAttempt{{after: time.Hour}, {after: time.Hour}}}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = policies.unaryInterceptor(ctx, hedgedMethod, &pb.UnaryRequest{}, &pb.UnaryResponse{}, nil, fake.invoke)
+	fake.wait(t, 2)
+	if code := status.Code(err); code != codes.DeadlineExceeded {
+		t.Errorf("code = %v, want DeadlineExceeded: %v", code, err)
+	}
+	if len(fake.canceled) != 2 {
+		t.Errorf("canceled attempts %v, want both", fake.canceled)
+	}
+}
+
+func TestHedgingPolicyUnknownMethod(t *testing.T) {
+	policies, err := parseHedgingPolicies(hedgingConfig(3, "0s", "UNAVAILABLE"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake := &fakeInvoker{attempts: []fakeAttempt{{code: codes.Unavailable}}}
+	err = policies.unaryInterceptor(context.Background(), "/message.TypesService/Echo", &pb.UnaryRequest{}, &pb.UnaryResponse{}, nil, fake.invoke)
+	if code := status.Code(err); code != codes.Unavailable || len(fake.started) != 1 {
+		t.Errorf("got %v after %d attempts, want a single UNAVAILABLE attempt", err, len(fake.started))
+	}
+}
+
+func TestParseHedgingPoliciesErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config string
+	}{
+		{"single attempt", hedgingConfig(1, "0s")},
+		{"invalid delay", hedgingConfig(2, "soon")},
+		{"unknown code", hedgingConfig(2, "0s", "NOT_A_CODE")},
+		{"retry and hedging", `{"methodConfig": [{"name": [{"service": "s"}], "retryPolicy": {}, "hedgingPolicy": {"maxAttempts": 2}}]}`},
+		{"invalid json", `{"methodConfig": `},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseHedgingPolicies(tt.config); err == nil {
+				t.Errorf("parseHedgingPolicies(%s) succeeded, want an error", tt.config)
+			}
+		})
+	}
+}
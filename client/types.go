@@ -0,0 +1,226 @@
+package main
+
+import (
+	"bytes"
+	"client/message/pb"
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// clientUnknownFields are sent with every request, the server has to keep them in its echo
+var clientUnknownFields = func() []byte {
+	b := protowire.AppendTag(nil, 2000, protowire.BytesType)
+	return protowire.AppendString(b, "unknown field from the client")
+}()
+
+// sampleAllTypes fills every field of AllTypes, i makes the messages of a stream differ
+func sampleAllTypes(i int) *pb.AllTypes {
+	scalars := &pb.Scalars{
+		DoubleValue:   math.Pi * float64(i+1),
+		FloatValue:    math.E,
+		Int32Value:    -int32(i) - 1,
+		Int64Value:    math.MinInt64 + int64(i),
+		Uint32Value:   math.MaxUint32,
+		Uint64Value:   math.MaxUint64 - uint64(i),
+		Sint32Value:   -150,
+		Sint64Value:   math.MinInt64,
+		Fixed32Value:  0xdeadbeef,
+		Fixed64Value:  0xfeedfacecafebeef,
+		Sfixed32Value: math.MinInt32,
+		Sfixed64Value: -1,
+		BoolValue:     true,
+		StringValue:   fmt.Sprintf("message %d, ünïcödé ✓", i),
+		BytesValue:    []byte{0x00, 0xff, 0x80, 0x7f},
+	}
+	nested := &pb.Nested{Name: "root", Child: &pb.Nested{Name: "child", Child: &pb.Nested{Name: "grandchild"}}, Scalars: scalars}
+	anyValue, _ := anypb.New(&pb.Nested{Name: "inside any"})
+	structValue, _ := structpb.NewStruct(map[string]any{
+		"string": "value",
+		"number": 1.5,
+		"bool":   true,
+		"null":   nil,
+		"list":   []any{1, "two", false},
+		"object": map[string]any{"nested": "yes"},
+	})
+	listValue, _ := structpb.NewList([]any{"a", 2, nil})
+	optional := int32(0)
+	m := &pb.AllTypes{
+		Scalars:        scalars,
+		Nested:         nested,
+		PackedInt32:    []int32{1, -1, 300, math.MaxInt32},
+		PackedDouble:   []float64{0.5, -0.25, math.Inf(1)},
+		UnpackedInt32:  []int32{3, 270, 86942},
+		UnpackedSint64: []int64{-1, 1, math.MinInt64},
+		Strings:        []string{"", "one", "two"},
+		BytesList:      [][]byte{{}, {1, 2, 3}},
+		NestedList:     []*pb.Nested{{Name: "first"}, {Name: "second"}},
+		StringToInt32:  map[string]int32{"a": 1, "b": -2},
+		Int64ToNested:  map[int64]*pb.Nested{-5: {Name: "minus five"}, 7: {Name: "seven"}},
+		BoolToBytes:    map[bool][]byte{true: {1}, false: {0}},
+		Uint32ToColor:  map[uint32]pb.Color{1: pb.Color_COLOR_RED, 2: pb.Color_COLOR_NEGATIVE},
+		Choice:         &pb.AllTypes_ChoiceNested{ChoiceNested: &pb.Nested{Name: "chosen"}},
+		Color:          pb.Color_COLOR_BLUE,
+		Colors:         []pb.Color{pb.Color_COLOR_RED, pb.Color_COLOR_GREEN, pb.Color_COLOR_NEGATIVE},
+		Any:            anyValue,
+		Struct:         structValue,
+		Value:          structpb.NewStringValue("value"),
+		ListValue:      listValue,
+		Timestamp:      timestamppb.New(time.Date(2024, 2, 29, 12, 0, 0, 123456789, time.UTC)),
+		Duration:       durationpb.New(-1500 * time.Millisecond),
+		DoubleWrapper:  wrapperspb.Double(0),
+		FloatWrapper:   wrapperspb.Float(1.25),
+		Int64Wrapper:   wrapperspb.Int64(math.MaxInt64),
+		Uint64Wrapper:  wrapperspb.UInt64(0),
+		Int32Wrapper:   wrapperspb.Int32(-7),
+		Uint32Wrapper:  wrapperspb.UInt32(7),
+		BoolWrapper:    wrapperspb.Bool(false),
+		StringWrapper:  wrapperspb.String(""),
+		BytesWrapper:   wrapperspb.Bytes([]byte("wrapped")),
+		OptionalInt32:  &optional,
+		MaxFieldNumber: "the largest field number",
+	}
+	m.ProtoReflect().SetUnknown(clientUnknownFields)
+	return m
+}
+
+// checkEcho verifies that resp is req with the server unknown fields appended,
+// codecs without unknown fields only have to echo the known ones.
+func checkEcho(req, resp *pb.AllTypes, unknownFields bool) error {
+	unknown := resp.ProtoReflect().GetUnknown()
+	if unknownFields && !bytes.HasPrefix(unknown, clientUnknownFields) {
+		return fmt.Errorf("the client unknown fields were lost")
+	}
+	if unknownFields && len(unknown) == len(clientUnknownFields) {
+		return fmt.Errorf("no server unknown fields")
+	}
+	req, resp = proto.Clone(req).(*pb.AllTypes), proto.Clone(resp).(*pb.AllTypes)
+	req.ProtoReflect().SetUnknown(nil)
+	resp.ProtoReflect().SetUnknown(nil)
+	if !proto.Equal(req, resp) {
+		return fmt.Errorf("echo differs from the request")
+	}
+	return nil
+}
+
+// runTypes calls every TypesService method with fully populated messages and checks the echoes,
+// it returns the process exit code. The json codec drops unknown fields, so they are only checked
+// with the binary codecs.
+func runTypes(client pb.TypesServiceClient, codec string) int {
+	unknownFields := codec != "json"
+	checks := []struct {
+		name string
+		run  func(ctx context.Context) (int, error)
+	}{
+		{"Echo", func(ctx context.Context) (int, error) {
+			req := sampleAllTypes(0)
+			resp, err := client.Echo(ctx, req)
+			if err != nil {
+				return 0, err
+			}
+			return proto.Size(resp), checkEcho(req, resp, unknownFields)
+		}},
+		{"EchoServerStream", func(ctx context.Context) (int, error) {
+			template := sampleAllTypes(0)
+			stream, err := client.EchoServerStream(ctx, &pb.TypesStreamRequest{Template: template, Count: 3})
+			if err != nil {
+				return 0, err
+			}
+			size, count := 0, 0
+			for {
+				resp, err := stream.Recv()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					return size, err
+				}
+				if resp.GetChoiceInt64() != int64(count) || !proto.Equal(resp.GetScalars(), template.GetScalars()) {
+					return size, fmt.Errorf("message %d differs from the template", count)
+				}
+				size += proto.Size(resp)
+				count++
+			}
+			if count != 3 {
+				return size, fmt.Errorf("expected 3 messages, got %d", count)
+			}
+			return size, nil
+		}},
+		{"EchoClientStream", func(ctx context.Context) (int, error) {
+			stream, err := client.EchoClientStream(ctx)
+			if err != nil {
+				return 0, err
+			}
+			var last *pb.AllTypes
+			for i := 0; i < 3; i++ {
+				last = sampleAllTypes(i)
+				if err := stream.Send(last); err != nil {
+					break // the real error is returned by CloseAndRecv
+				}
+			}
+			summary, err := stream.CloseAndRecv()
+			if err != nil {
+				return 0, err
+			}
+			if summary.GetCount() != 3 {
+				return proto.Size(summary), fmt.Errorf("server received %d messages, expected 3", summary.GetCount())
+			}
+			return proto.Size(summary), checkEcho(last, summary.GetLast(), unknownFields)
+		}},
+		{"EchoBidiStream", func(ctx context.Context) (int, error) {
+			stream, err := client.EchoBidiStream(ctx)
+			if err != nil {
+				return 0, err
+			}
+			size := 0
+			for i := 0; i < 3; i++ {
+				req := sampleAllTypes(i)
+				if err := stream.Send(req); err != nil {
+					break // the real error is returned by Recv
+				}
+				resp, err := stream.Recv()
+				if err != nil {
+					return size, err
+				}
+				size += proto.Size(resp)
+				if err := checkEcho(req, resp, unknownFields); err != nil {
+					return size, fmt.Errorf("message %d: %w", i, err)
+				}
+			}
+			stream.CloseSend()
+			if _, err := stream.Recv(); err != io.EOF {
+				return size, fmt.Errorf("expected the end of the stream, got %v", err)
+			}
+			return size, nil
+		}},
+	}
+
+	failures := 0
+	for _, check := range checks {
+		ctx, cancel := context.WithTimeout(metadata.NewOutgoingContext(context.Background(), uniformHeader("types")), 10*time.Second)
+		size, err := check.run(ctx)
+		cancel()
+		if err != nil {
+			failures++
+			fmt.Printf("FAIL %s: %v\n", check.name, err)
+		} else {
+			fmt.Printf("PASS %s: %d response bytes\n", check.name, size)
+		}
+	}
+	fmt.Printf("%d methods, %d passed, %d failed\n", len(checks), len(checks)-failures, failures)
+	if failures > 0 {
+		return exitFailure
+	}
+	return exitOK
+}
@@ -0,0 +1,289 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/status"
+)
+
+// streamingServiceName is the health service name of StreamingService, "" is the whole server
+const streamingServiceName = "message.StreamingService"
+
+// adminServer serves the runtime admin endpoints on -admin-addr:
+//
+//	GET  /health                                    list the health status of every service
+//	POST /health?service=<name>&status=NOT_SERVING  change a status, service defaults to the whole server
+//	GET  /config                                    show the runtime config
+//	PUT  /config {"delay_ms": 100}                  change the given fields of the runtime config
+//	GET  /streams                                   list the in-flight rpcs
+//	POST /streams/kill?id=<id>&code=ABORTED&message= end one rpc with a status, CANCELLED by default
+//	GET  /replicas                                  list the replicas and their state
+//	POST /replicas/kill?replica=<n>&graceful=true   stop a replica, its rpcs are cut unless graceful
+//	POST /replicas/start?replica=<n>                start a stopped replica again
+//	POST /replicas/restart?replica=<n>              drain, stop and start a replica, graceful=false cuts its rpcs
+//
+// A graceful kill or restart answers 202 Accepted with the replica stopping, GET /replicas tells when
+// the drain is over.
+//
+// The health, config and streams endpoints act on the replica given by ?replica=<n>, the first one by default.
+type adminServer struct {
+	mux     *http.ServeMux
+	cluster *cluster
+}
+
+func newAdminServer(c *cluster) *adminServer {
+	a := &adminServer{
+		mux:     http.NewServeMux(),
+		cluster: c,
+	}
+	a.mux.HandleFunc("/health", a.handleHealth)
+	a.mux.HandleFunc("/config", a.handleConfig)
+	a.mux.HandleFunc("/streams", a.handleStreams)
+	a.mux.HandleFunc("/streams/kill", a.handleKillStream)
+	a.mux.HandleFunc("/replicas", a.handleReplicas)
+	a.mux.HandleFunc("/replicas/kill", a.handleReplicaAction)
+	a.mux.HandleFunc("/replicas/start", a.handleReplicaAction)
+	a.mux.HandleFunc("/replicas/restart", a.handleReplicaAction)
+	return a
+}
+
+func (a *adminServer) serve(addr string) {
+	fmt.Printf("serving admin api on %s\n", addr)
+	if err := http.ListenAndServe(addr, a.mux); err != nil {
+		fmt.Printf("failed to serve admin api: %v\n", err)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// replica returns the replica of the ?replica= parameter, it writes the error when there is none
+func (a *adminServer) replica(w http.ResponseWriter, r *http.Request) *replica {
+	index := 0
+	if v := r.URL.Query().Get("replica"); v != "" {
+		var err error
+		if index, err = strconv.Atoi(v); err != nil {
+			http.Error(w, fmt.Sprintf("invalid replica %q", v), http.StatusBadRequest)
+			return nil
+		}
+	}
+	rep := a.cluster.replica(index)
+	if rep == nil {
+		http.Error(w, fmt.Sprintf("no replica %d", index), http.StatusNotFound)
+	}
+	return rep
+}
+
+// run returns the current run of the ?replica= parameter, it writes the error when it is stopped
+func (a *adminServer) run(w http.ResponseWriter, r *http.Request) *replicaRun {
+	rep := a.replica(w, r)
+	if rep == nil {
+		return nil
+	}
+	run := rep.current()
+	if run == nil {
+		http.Error(w, fmt.Sprintf("replica %s is stopped", rep.identity), http.StatusServiceUnavailable)
+	}
+	return run
+}
+
+func (a *adminServer) handleHealth(w http.ResponseWriter, r *http.Request) {
+	run := a.run(w, r)
+	if run == nil {
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost, http.MethodPut:
+		service := r.URL.Query().Get("service")
+		value, ok := healthpb.HealthCheckResponse_ServingStatus_value[r.URL.Query().Get("status")]
+		if !ok {
+			http.Error(w, "status must be one of SERVING, NOT_SERVING, SERVICE_UNKNOWN", http.StatusBadRequest)
+			return
+		}
+		status := healthpb.HealthCheckResponse_ServingStatus(value)
+		run.mu.Lock()
+		run.services[service] = true
+		run.mu.Unlock()
+		// SetServingStatus pushes the new status to every Watch stream of the service
+		run.health.SetServingStatus(service, status)
+		fmt.Printf("health of %q set to %v\n", service, status)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	run.mu.Lock()
+	services := make([]string, 0, len(run.services))
+	for service := range run.services {
+		services = append(services, service)
+	}
+	run.mu.Unlock()
+	sort.Strings(services)
+	result := make([]map[string]string, 0, len(services))
+	for _, service := range services {
+		resp, err := run.health.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		status := healthpb.HealthCheckResponse_SERVICE_UNKNOWN
+		if err == nil {
+			status = resp.GetStatus()
+		}
+		result = append(result, map[string]string{"service": service, "status": status.String()})
+	}
+	writeJSON(w, result)
+}
+
+func (a *adminServer) handleConfig(w http.ResponseWriter, r *http.Request) {
+	// the runtime config of a stopped replica can be changed too, it is kept for its next start
+	rep := a.replica(w, r)
+	if rep == nil {
+		return
+	}
+	switch r.Method {
+	case http.MethodGet:
+		writeJSON(w, rep.settings.get())
+	case http.MethodPost, http.MethodPut, http.MethodPatch:
+		// decoding over the current config keeps the fields missing from the body, the body is read
+		// before the update, so a slow admin client never holds the settings the rpcs read
+		cfg := rep.settings.get()
+		decoder := json.NewDecoder(r.Body)
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&cfg); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := rep.settings.update(cfg); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		fmt.Printf("runtime config of %s set to %+v\n", rep.identity, cfg)
+		writeJSON(w, cfg)
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func (a *adminServer) handleStreams(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	run := a.run(w, r)
+	if run == nil {
+		return
+	}
+	rpcs := run.registry.inflight()
+	result := make([]rpcInfo, 0, len(rpcs))
+	for _, rpc := range rpcs {
+		result = append(result, rpc.info())
+	}
+	writeJSON(w, result)
+}
+
+func (a *adminServer) handleKillStream(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost && r.Method != http.MethodDelete {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	run := a.run(w, r)
+	if run == nil {
+		return
+	}
+	query := r.URL.Query()
+	id, err := strconv.ParseUint(query.Get("id"), 10, 64)
+	if err != nil {
+		http.Error(w, "id must be the id of an in-flight rpc", http.StatusBadRequest)
+		return
+	}
+	code := codes.Canceled
+	if c := query.Get("code"); c != "" {
+		if code, err = parseCode(c); err != nil || code == codes.OK {
+			http.Error(w, fmt.Sprintf("invalid code %q", c), http.StatusBadRequest)
+			return
+		}
+	}
+	message := query.Get("message")
+	if message == "" {
+		message = fmt.Sprintf("rpc killed by admin with %v", code)
+	}
+	if !run.registry.kill(id, status.New(code, message)) {
+		http.Error(w, fmt.Sprintf("no in-flight rpc %d", id), http.StatusNotFound)
+		return
+	}
+	writeJSON(w, map[string]any{"id": id, "code": code.String(), "message": message})
+}
+
+func (a *adminServer) handleReplicas(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	result := make([]replicaInfo, 0, len(a.cluster.replicas))
+	for _, rep := range a.cluster.replicas {
+		result = append(result, rep.info())
+	}
+	writeJSON(w, result)
+}
+
+// handleReplicaAction kills, starts or restarts one replica. A graceful stop drains in the background,
+// so an admin client giving up on a long drain never leaves the replica half restarted.
+func (a *adminServer) handleReplicaAction(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	rep := a.replica(w, r)
+	if rep == nil {
+		return
+	}
+	action := strings.TrimPrefix(r.URL.Path, "/replicas/")
+	graceful := action == "restart"
+	if v := r.URL.Query().Get("graceful"); v != "" {
+		var err error
+		if graceful, err = strconv.ParseBool(v); err != nil {
+			http.Error(w, fmt.Sprintf("invalid graceful %q", v), http.StatusBadRequest)
+			return
+		}
+	}
+	var err error
+	accepted := false
+	restart := func() {
+		if err := rep.start(); err != nil {
+			fmt.Printf("replica %s failed to restart: %v\n", rep.identity, err)
+		}
+	}
+	switch action {
+	case "kill":
+		if graceful {
+			err, accepted = rep.stopInBackground(true, nil), true
+		} else {
+			err = rep.stop(false)
+		}
+	case "start":
+		err = rep.start()
+	case "restart":
+		if graceful {
+			err, accepted = rep.stopInBackground(true, restart), true
+		} else if err = rep.stop(false); err == nil {
+			err = rep.start()
+		}
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if accepted {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+	}
+	writeJSON(w, rep.info())
+}
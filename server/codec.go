@@ -0,0 +1,67 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// The extra codecs are picked per call by the content-subtype, e.g. application/grpc+json,
+// the default application/grpc and application/grpc+proto stay on the proto codec.
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+	encoding.RegisterCodec(demoCodec{})
+}
+
+// jsonCodec encodes messages as protojson, for application/grpc+json
+type jsonCodec struct{}
+
+func (jsonCodec) Name() string { return "json" }
+
+func (jsonCodec) Marshal(v any) ([]byte, error) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("json codec: %T is not a proto message", v)
+	}
+	return protojson.Marshal(m)
+}
+
+func (jsonCodec) Unmarshal(data []byte, v any) error {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("json codec: %T is not a proto message", v)
+	}
+	// a newer peer may send fields this side does not know yet
+	return protojson.UnmarshalOptions{DiscardUnknown: true}.Unmarshal(data, m)
+}
+
+// demoMagic starts every message of the demo codec
+var demoMagic = []byte("GSD1")
+
+// demoCodec is a deliberately custom format for application/grpc+demo: the proto wire
+// bytes behind a magic number, which no standard decoder understands as is.
+type demoCodec struct{}
+
+func (demoCodec) Name() string { return "demo" }
+
+func (demoCodec) Marshal(v any) ([]byte, error) {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("demo codec: %T is not a proto message", v)
+	}
+	return proto.MarshalOptions{}.MarshalAppend(append([]byte{}, demoMagic...), m)
+}
+
+func (demoCodec) Unmarshal(data []byte, v any) error {
+	m, ok := v.(proto.Message)
+	if !ok {
+		return fmt.Errorf("demo codec: %T is not a proto message", v)
+	}
+	if !bytes.HasPrefix(data, demoMagic) {
+		return fmt.Errorf("demo codec: missing magic %q", demoMagic)
+	}
+	return proto.Unmarshal(data[len(demoMagic):], m)
+}
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"encoding/json"
+	"testing"
+
+	"server/message/pb"
+
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestCodecsDecodeRequests(t *testing.T) {
+	requests := []proto.Message{
+		&pb.UnaryRequest{Message: "hello", Meta: &pb.MessageMeta{StreamId: "0123456789abcdef", Seq: 1, Checksum: 7}},
+		&pb.ServerStreamRequest{Message: "hello", ResponseCount: 5, IntervalMs: -1, PayloadSize: 1 << 10},
+		&pb.BidirectionalStreamRequest{Message: "hello", ResponsesPerRequest: 2, Payload: []byte{0, 0xff}},
+	}
+	for _, name := range []string{"json", "demo"} {
+		codec := encoding.GetCodec(name)
+		if codec == nil {
+			t.Fatalf("codec %s is not registered", name)
+		}
+		for _, req := range requests {
+			data, err := codec.Marshal(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			got := req.ProtoReflect().New().Interface()
+			if err := codec.Unmarshal(data, got); err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			if !proto.Equal(got, req) {
+				t.Errorf("%s decoded %v, want %v", name, got, req)
+			}
+		}
+	}
+}
+
+func TestJSONCodecResponse(t *testing.T) {
+	data, err := jsonCodec{}.Marshal(&pb.ServerStreamResponse{
+		Response: "hello",
+		Payload:  []byte{1, 2},
+		Meta:     &pb.MessageMeta{Seq: 1, AckSeq: 1},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the spacing of protojson is not stable, compare the decoded object
+	var got map[string]any
+	if err := json.Unmarshal(data, &got); err != nil {
+		t.Fatalf("%s: %v", data, err)
+	}
+	meta, _ := got["meta"].(map[string]any)
+	if got["response"] != "hello" || got["payload"] != "AQI=" || meta["seq"] != "1" || meta["ackSeq"] != "1" {
+		t.Errorf("json response %s, want lowerCamelCase fields, a base64 payload and uint64 strings", data)
+	}
+}
+
+func TestCodecsRejectMalformedRequests(t *testing.T) {
+	// a client sending application/grpc+demo with plain proto bytes
+	wire, err := proto.Marshal(&pb.UnaryRequest{Message: "hello"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := (demoCodec{}).Unmarshal(wire, &pb.UnaryRequest{}); err == nil {
+		t.Error("plain proto request decoded as a demo message")
+	}
+	if err := (jsonCodec{}).Unmarshal([]byte(`{"message": 1}`), &pb.UnaryRequest{}); err == nil {
+		t.Error("json request with a number message decoded")
+	}
+}
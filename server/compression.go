@@ -0,0 +1,153 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+// Request metadata understood by the compression interceptors:
+//
+//	x-response-compression: compressor of the responses, gzip, zstd, snappy or identity
+//	x-uncompressed-every:   send every Nth stream response uncompressed, 0 never
+//
+// Both override the -compression and -uncompressed-every flags for one call.
+const (
+	responseCompressionKey = "x-response-compression"
+	uncompressedEveryKey   = "x-uncompressed-every"
+)
+
+// compressionConfig is filled by the server flags
+type compressionConfig struct {
+	// Response is the compressor of every response, empty answers with the compressor of the request
+	Response string
+	// UncompressedEvery sends every Nth stream response without compression, 0 never
+	UncompressedEvery int
+}
+
+func (c compressionConfig) validate() error {
+	if c.Response != "" && c.Response != encoding.Identity && encoding.GetCompressor(c.Response) == nil {
+		return fmt.Errorf("unknown compressor: %s", c.Response)
+	}
+	if c.UncompressedEvery < 0 {
+		return fmt.Errorf("uncompressed-every must not be negative: %d", c.UncompressedEvery)
+	}
+	return nil
+}
+
+// compressor sets the response compression of every call. It is also a stats.Handler,
+// only to learn the compression of the request, which grpc keeps out of the metadata.
+type compressor struct {
+	cfg compressionConfig
+}
+
+type requestCompressionKey struct{}
+
+// requestCompression is filled by the InHeader event, before the handler runs
+type requestCompression struct {
+	name string
+}
+
+func (c *compressor) TagRPC(ctx context.Context, _ *stats.RPCTagInfo) context.Context {
+	return context.WithValue(ctx, requestCompressionKey{}, &requestCompression{})
+}
+
+func (c *compressor) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	if in, ok := s.(*stats.InHeader); ok {
+		if rc, ok := ctx.Value(requestCompressionKey{}).(*requestCompression); ok {
+			rc.name = in.Compression
+		}
+	}
+}
+
+func (c *compressor) TagConn(ctx context.Context, _ *stats.ConnTagInfo) context.Context { return ctx }
+
+func (c *compressor) HandleConn(context.Context, stats.ConnStats) {}
+
+// setup applies the response compressor of the call and returns its uncompressed-every
+func (c *compressor) setup(ctx context.Context) (int, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	name, every := c.cfg.Response, c.cfg.UncompressedEvery
+	if v := mdValue(md, uncompressedEveryKey); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return 0, status.Errorf(codes.InvalidArgument, "invalid %s: %q", uncompressedEveryKey, v)
+		}
+		every = n
+	}
+	if v := mdValue(md, responseCompressionKey); v != "" {
+		// asked for by the client, so it has to work
+		if err := grpc.SetSendCompressor(ctx, v); err != nil {
+			return 0, status.Errorf(codes.InvalidArgument, "invalid %s: %v", responseCompressionKey, err)
+		}
+		return every, nil
+	}
+	if name != "" {
+		// the flag default only applies to clients that can decompress it
+		supported, _ := grpc.ClientSupportedCompressors(ctx)
+		if name == encoding.Identity || slices.Contains(supported, name) {
+			if err := grpc.SetSendCompressor(ctx, name); err != nil {
+				fmt.Printf("failed to set the response compressor %s: %v\n", name, err)
+			}
+		}
+	}
+	return every, nil
+}
+
+func (c *compressor) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	// a single response has nothing to alternate with, uncompressed-every is for streams
+	if _, err := c.setup(ctx); err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (c *compressor) streamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	every, err := c.setup(ss.Context())
+	if err != nil {
+		return err
+	}
+	if every == 0 {
+		return handler(srv, ss)
+	}
+	if rc, ok := ss.Context().Value(requestCompressionKey{}).(*requestCompression); ok && rc.name != "" && rc.name != encoding.Identity {
+		fmt.Printf("%s: requests are compressed with %s, every response is compressed\n", info.FullMethod, rc.name)
+		return handler(srv, ss)
+	}
+	s := &mixedCompressionStream{ServerStream: ss, every: every}
+	err = handler(srv, s)
+	fmt.Printf("%s: %d of %d responses sent uncompressed\n", info.FullMethod, s.uncompressed, s.sent)
+	return err
+}
+
+// mixedCompressionStream sends every Nth message without compression, while the others
+// use the response compressor of the stream
+type mixedCompressionStream struct {
+	grpc.ServerStream
+	every        int
+	sent         int
+	uncompressed int
+}
+
+func (s *mixedCompressionStream) SendMsg(m any) error {
+	s.sent++
+	if s.sent%s.every != 0 {
+		return s.ServerStream.SendMsg(m)
+	}
+	// grpc-go has no per-message switch, but a PreparedMsg is encoded with the compressor the stream
+	// started with, none for uncompressed requests, and sent as is whatever SetSendCompressor chose
+	msg := &grpc.PreparedMsg{}
+	if err := msg.Encode(s.ServerStream, m); err != nil {
+		return err
+	}
+	s.uncompressed++
+	return s.ServerStream.SendMsg(msg)
+}
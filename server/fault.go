@@ -0,0 +1,322 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// Request metadata understood by the fault interceptors:
+//
+//	x-fault-code:           status code to fail with, name (UNAVAILABLE) or number (14)
+//	x-fault-message:        status message
+//	x-fault-reason:         reason of the ErrorInfo detail
+//	x-fault-retry-delay:    adds a RetryInfo detail, e.g. 500ms
+//	x-fault-debug:          adds a DebugInfo detail with this text
+//	x-fault-after:          fail after N messages sent (received for client streams), 0 fails at once
+//	x-fault-trailers-only:  "true" fails at once without response headers, as a Trailers-Only response
+//	x-fault-delay:          wait before the handler runs, e.g. 500ms, cut short when the call is canceled
+//	x-fault-attempts:       only the first N attempts of a retried or hedged call get the fault,
+//	                        counted by grpc-previous-rpc-attempts
+//	x-fault-header-<name>:  set response header <name>
+//	x-fault-trailer-<name>: set response trailer <name>
+//
+// Without x-fault-code, StreamingService rpcs also fail at the error_rate of the runtime config.
+const (
+	faultCodeKey          = "x-fault-code"
+	faultMessageKey       = "x-fault-message"
+	faultReasonKey        = "x-fault-reason"
+	faultRetryDelayKey    = "x-fault-retry-delay"
+	faultDebugKey         = "x-fault-debug"
+	faultAfterKey         = "x-fault-after"
+	faultTrailersOnlyKey  = "x-fault-trailers-only"
+	faultDelayKey         = "x-fault-delay"
+	faultAttemptsKey      = "x-fault-attempts"
+	faultHeaderPrefix     = "x-fault-header-"
+	faultTrailerPrefix    = "x-fault-trailer-"
+	faultErrorInfoDomain  = "grpc-stream-demo"
+	faultDefaultErrReason = "INJECTED_FAULT"
+	faultRandomErrReason  = "RANDOM_FAULT"
+)
+
+type fault struct {
+	status       *status.Status
+	after        int
+	trailersOnly bool
+	delay        time.Duration
+	header       metadata.MD
+	trailer      metadata.MD
+}
+
+func mdValue(md metadata.MD, key string) string {
+	if v := md.Get(key); len(v) > 0 {
+		return v[0]
+	}
+	return ""
+}
+
+// previousAttemptsKey is sent by grpc on retried and hedged attempts
+const previousAttemptsKey = "grpc-previous-rpc-attempts"
+
+// previousAttempts reads the grpc-previous-rpc-attempts header of retried and hedged attempts
+func previousAttempts(md metadata.MD) int {
+	n, _ := strconv.Atoi(mdValue(md, previousAttemptsKey))
+	return n
+}
+
+// parseCode reads a status code by name, e.g. UNAVAILABLE, or by number, from 0 to 16
+func parseCode(s string) (codes.Code, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		if n < int(codes.OK) || n > int(codes.Unauthenticated) {
+			return codes.Unknown, fmt.Errorf("invalid code %d", n)
+		}
+		return codes.Code(n), nil
+	}
+	var code codes.Code
+	err := code.UnmarshalJSON([]byte(strconv.Quote(strings.ToUpper(s))))
+	return code, err
+}
+
+// faultStatus builds the status of an injected fault, with an ErrorInfo detail naming the method
+func faultStatus(code codes.Code, msg, reason, method string, details ...protoadapt.MessageV1) *status.Status {
+	if msg == "" {
+		msg = fmt.Sprintf("injected fault %v", code)
+	}
+	if reason == "" {
+		reason = faultDefaultErrReason
+	}
+	st := status.New(code, msg)
+	if code == codes.OK {
+		return st
+	}
+	details = append([]protoadapt.MessageV1{&errdetails.ErrorInfo{
+		Reason:   reason,
+		Domain:   faultErrorInfoDomain,
+		Metadata: map[string]string{"method": method},
+	}}, details...)
+	if withDetails, err := st.WithDetails(details...); err == nil {
+		return withDetails
+	}
+	return st
+}
+
+// parseFault reads the fault asked by the request metadata, or rolled from the runtime error rate
+// when the metadata asks for none. It returns nil when there is no fault. Without withMetadata
+// only the runtime error rate applies.
+func parseFault(ctx context.Context, method string, cfg runtimeConfig, withMetadata bool) (*fault, error) {
+	var md metadata.MD
+	if withMetadata {
+		md, _ = metadata.FromIncomingContext(ctx)
+	}
+	if a := mdValue(md, faultAttemptsKey); a != "" {
+		attempts, err := strconv.Atoi(a)
+		if err != nil || attempts < 0 {
+			return nil, fmt.Errorf("invalid %s %q", faultAttemptsKey, a)
+		}
+		if previousAttempts(md) >= attempts {
+			return nil, nil
+		}
+	}
+	f := &fault{header: metadata.MD{}, trailer: metadata.MD{}}
+	for k, v := range md {
+		if name, ok := strings.CutPrefix(k, faultHeaderPrefix); ok {
+			f.header.Append(name, v...)
+		}
+		if name, ok := strings.CutPrefix(k, faultTrailerPrefix); ok {
+			f.trailer.Append(name, v...)
+		}
+	}
+
+	if c := mdValue(md, faultCodeKey); c != "" {
+		code, err := parseCode(c)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q", faultCodeKey, c)
+		}
+		var details []protoadapt.MessageV1
+		if d := mdValue(md, faultRetryDelayKey); d != "" {
+			delay, err := time.ParseDuration(d)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s %q", faultRetryDelayKey, d)
+			}
+			details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(delay)})
+		}
+		if d := mdValue(md, faultDebugKey); d != "" {
+			details = append(details, &errdetails.DebugInfo{Detail: d})
+		}
+		f.status = faultStatus(code, mdValue(md, faultMessageKey), mdValue(md, faultReasonKey), method, details...)
+	} else if strings.HasPrefix(method, "/"+streamingServiceName+"/") && cfg.randomError() {
+		// health checks and reflection are never failed at random, only the demo service
+		code := cfg.errorCode()
+		f.status = faultStatus(code, fmt.Sprintf("random fault %v", code), faultRandomErrReason, method)
+	}
+	if a := mdValue(md, faultAfterKey); a != "" {
+		after, err := strconv.Atoi(a)
+		if err != nil || after < 0 {
+			return nil, fmt.Errorf("invalid %s %q", faultAfterKey, a)
+		}
+		f.after = after
+	}
+	f.trailersOnly = mdValue(md, faultTrailersOnlyKey) == "true"
+	if d := mdValue(md, faultDelayKey); d != "" {
+		delay, err := time.ParseDuration(d)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q", faultDelayKey, d)
+		}
+		f.delay = delay
+	}
+
+	if f.status == nil && f.delay == 0 && len(f.header) == 0 && len(f.trailer) == 0 {
+		return nil, nil
+	}
+	return f, nil
+}
+
+// apply stages the custom headers and trailers, and sends the headers at once when the fault
+// fires before any message, so the client gets a headers frame instead of Trailers-Only.
+func (f *fault) apply(ctx context.Context, immediate bool) {
+	if len(f.trailer) > 0 {
+		grpc.SetTrailer(ctx, f.trailer)
+	}
+	if f.trailersOnly {
+		return
+	}
+	if len(f.header) > 0 {
+		grpc.SetHeader(ctx, f.header)
+	}
+	if immediate {
+		grpc.SendHeader(ctx, metadata.MD{})
+	}
+}
+
+// wait sleeps the delay of the fault, it returns early with the status of a canceled call,
+// e.g. an attempt beaten by a hedged one
+func (f *fault) wait(ctx context.Context) error {
+	if f.delay <= 0 {
+		return nil
+	}
+	t := time.NewTimer(f.delay)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	}
+}
+
+func (f *fault) err() error {
+	if f.status == nil || f.status.Code() == codes.OK {
+		return nil
+	}
+	return f.status.Err()
+}
+
+// immediate reports whether the fault fires before the handler runs
+func (f *fault) immediate(streaming bool) bool {
+	return f.err() != nil && (f.trailersOnly || !streaming || f.after == 0)
+}
+
+// faultInjector fails rpcs as asked by their metadata or by the runtime error rate
+type faultInjector struct {
+	settings *runtimeSettings
+	// relay leaves the x-fault-* metadata to the last server, only the runtime error rate applies
+	relay bool
+}
+
+func (i *faultInjector) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	f, err := parseFault(ctx, info.FullMethod, i.settings.get(), !i.relay)
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if f == nil {
+		return handler(ctx, req)
+	}
+	if err := f.wait(ctx); err != nil {
+		return nil, err
+	}
+	if f.immediate(false) {
+		fmt.Printf("inject fault into %s: %v\n", info.FullMethod, f.err())
+		f.apply(ctx, true)
+		return nil, f.err()
+	}
+	f.apply(ctx, false)
+	return handler(ctx, req)
+}
+
+func (i *faultInjector) streamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	f, err := parseFault(ss.Context(), info.FullMethod, i.settings.get(), !i.relay)
+	if err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if f == nil {
+		return handler(srv, ss)
+	}
+	if err := f.wait(ss.Context()); err != nil {
+		return err
+	}
+	if f.immediate(true) {
+		fmt.Printf("inject fault into %s: %v\n", info.FullMethod, f.err())
+		f.apply(ss.Context(), true)
+		return f.err()
+	}
+	f.apply(ss.Context(), false)
+	if f.err() == nil {
+		return handler(srv, ss)
+	}
+	fs := &faultStream{ServerStream: ss, fault: f, countSent: info.IsServerStream, method: info.FullMethod}
+	if err := handler(srv, fs); err != nil {
+		return err
+	}
+	// the handler finished before N messages, fail at the end of the stream instead
+	fs.fire()
+	return f.err()
+}
+
+// faultStream aborts the stream once N messages went through it
+type faultStream struct {
+	grpc.ServerStream
+	fault     *fault
+	countSent bool
+	method    string
+	count     int
+	fired     bool
+}
+
+func (s *faultStream) fire() {
+	if !s.fired {
+		s.fired = true
+		fmt.Printf("inject fault into %s after %d messages: %v\n", s.method, s.count, s.fault.err())
+	}
+}
+
+func (s *faultStream) SendMsg(m any) error {
+	if s.countSent {
+		if s.count >= s.fault.after {
+			s.fire()
+			return s.fault.err()
+		}
+		s.count++
+	}
+	return s.ServerStream.SendMsg(m)
+}
+
+func (s *faultStream) RecvMsg(m any) error {
+	if !s.countSent {
+		if s.count >= s.fault.after {
+			s.fire()
+			return s.fault.err()
+		}
+		s.count++
+	}
+	return s.ServerStream.RecvMsg(m)
+}
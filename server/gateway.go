@@ -0,0 +1,107 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+
+	"server/message/pb"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// newGatewayHandler serves the REST/JSON mapping of StreamingService, the gateway calls
+// the grpc server s through an in-memory connection, so it never shows up on the network.
+func newGatewayHandler(s *grpc.Server) (http.Handler, func(), error) {
+	pipeL := newPipeListener()
+	go func() {
+		if err := s.Serve(pipeL); err != nil {
+			fmt.Printf("failed to serve gateway grpc: %v\n", err)
+		}
+	}()
+	conn, err := grpc.NewClient("passthrough:///gateway",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return pipeL.dialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	closeConn := func() { conn.Close() }
+
+	// custom metadata goes through the default Grpc-Metadata- header prefix, while x- headers,
+	// such as the x-fault-* ones, are forwarded as they are
+	mux := runtime.NewServeMux(runtime.WithIncomingHeaderMatcher(func(key string) (string, bool) {
+		if strings.HasPrefix(strings.ToLower(key), "x-") {
+			return key, true
+		}
+		return runtime.DefaultHeaderMatcher(key)
+	}))
+	if err := pb.RegisterStreamingServiceHandler(context.Background(), mux, conn); err != nil {
+		closeConn()
+		return nil, nil, err
+	}
+	mux.HandlePath("GET", "/hello", func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
+		fmt.Println("http request touched")
+		w.Write([]byte("hello, http"))
+	})
+	// h2c lets HTTP/2 clients without TLS reach the gateway too
+	return h2c.NewHandler(mux, &http2.Server{}), closeConn, nil
+}
+
+// pipeListener hands the server ends of in-memory net.Pipe connections to the grpc server
+type pipeListener struct {
+	conns     chan net.Conn
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func newPipeListener() *pipeListener {
+	return &pipeListener{conns: make(chan net.Conn), done: make(chan struct{})}
+}
+
+func (l *pipeListener) Accept() (net.Conn, error) {
+	select {
+	case conn := <-l.conns:
+		return conn, nil
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *pipeListener) Close() error {
+	l.closeOnce.Do(func() { close(l.done) })
+	return nil
+}
+
+func (l *pipeListener) Addr() net.Addr { return pipeAddr{} }
+
+// dialContext connects to the listener, it waits for the server to accept the connection
+func (l *pipeListener) dialContext(ctx context.Context) (net.Conn, error) {
+	server, client := net.Pipe()
+	select {
+	case l.conns <- server:
+		return client, nil
+	case <-l.done:
+	case <-ctx.Done():
+	}
+	server.Close()
+	client.Close()
+	if ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+	return nil, net.ErrClosed
+}
+
+type pipeAddr struct{}
+
+func (pipeAddr) Network() string { return "pipe" }
+func (pipeAddr) String() string  { return "pipe" }
@@ -2,12 +2,118 @@ package main
 
 import (
 	"flag"
+	"fmt"
+	"os"
+	"time"
+
+	"google.golang.org/grpc"
 )
 
 func main() {
-	port := "38888"
-	flag.StringVar(&port, "port", port, "The server port")
-	flag.IntVar(&ServerDelay, "delay", ServerDelay, "The server delay, unit: ms")
+	cfg := serverConfig{Port: "38888", Gateway: true, DrainTimeout: 10 * time.Second, Runtime: runtimeConfig{DelayMs: 10, StreamLength: 3, Utilization: 1}, Replicas: 1, ReplicaName: "server"}
+	if hostname, err := os.Hostname(); err == nil {
+		cfg.ReplicaName = hostname
+	}
+	replicaRuntime := ""
+	metricsAddr := ""
+	var maxRecvSize, maxSendSize byteSize
+	tlsCfg := tlsConfig{Hosts: "grpc-server"}
+	tracing := tracingConfig{Exporter: "none", File: "server-traces.json", OTLPEndpoint: "localhost:4317"}
+	flag.StringVar(&cfg.Port, "port", cfg.Port, "The server port")
+	flag.IntVar(&cfg.Runtime.DelayMs, "delay", cfg.Runtime.DelayMs, "The server delay, unit: ms")
+	flag.Float64Var(&cfg.Runtime.ErrorRate, "error-rate", cfg.Runtime.ErrorRate, "Ratio of rpcs failed on purpose, from 0 to 1")
+	flag.StringVar(&cfg.Runtime.ErrorCode, "error-code", cfg.Runtime.ErrorCode, "Status code of the -error-rate failures, UNAVAILABLE by default")
+	flag.IntVar(&cfg.Runtime.StreamLength, "stream-length", cfg.Runtime.StreamLength, "Default number of ServerStreamRPC responses")
+	flag.IntVar(&cfg.Runtime.PayloadSize, "payload-size", cfg.Runtime.PayloadSize, "Default payload size of stream responses, in bytes")
+	flag.StringVar(&cfg.Compression.Response, "compression", cfg.Compression.Response, "Response compressor: gzip, zstd, snappy or identity, the compressor of the request by default")
+	flag.IntVar(&cfg.Compression.UncompressedEvery, "uncompressed-every", cfg.Compression.UncompressedEvery, "Send every Nth stream response uncompressed, 0 never")
+	flag.BoolVar(&cfg.Orca, "orca", cfg.Orca, "Send ORCA load reports for weighted_round_robin clients, per call and on the OpenRcaService")
+	flag.Float64Var(&cfg.Runtime.Utilization, "utilization", cfg.Runtime.Utilization, "CPU utilization reported by -orca at 100 calls per second, weighted round robin weighs the server 1/utilization")
+	flag.IntVar(&cfg.Replicas, "replicas", cfg.Replicas, "Number of server replicas, on consecutive ports from -port or on -socket-dir")
+	flag.StringVar(&cfg.SocketDir, "socket-dir", cfg.SocketDir, "Serve the replicas on unix sockets replica-<i>.sock in this dir instead of tcp ports")
+	flag.StringVar(&cfg.ReplicaName, "replica-name", cfg.ReplicaName, "Identity sent in the x-replica header and the responses, replica i is <name>-<i> when there are several")
+	flag.StringVar(&replicaRuntime, "replica-runtime", replicaRuntime, "JSON list of runtime configs of the replicas, inline or @file, e.g. '[{}, {\"delay_ms\": 200}, {\"error_rate\": 0.5}]'")
+	flag.StringVar(&cfg.Upstream, "upstream", cfg.Upstream, "Relay every StreamingService rpc to these servers instead of answering it, a comma separated list balanced round robin or a grpc target such as dns:///host:port")
+	flag.BoolVar(&cfg.Gateway, "gateway", cfg.Gateway, "Serve the REST/JSON gateway on the server port too, plaintext only")
+	flag.DurationVar(&cfg.DrainTimeout, "drain-timeout", cfg.DrainTimeout, "How long SIGINT/SIGTERM waits for in-flight rpcs before force-closing them")
+	flag.BoolVar(&cfg.Reflection, "reflection", cfg.Reflection, "Register the grpc server reflection services")
+	flag.BoolVar(&cfg.Channelz, "channelz", cfg.Channelz, "Register the grpc admin services, channelz for sockets, streams and flow control windows")
+	flag.StringVar(&cfg.AdminAddr, "admin-addr", cfg.AdminAddr, "Address of the admin http api, e.g. :38889, disabled when empty")
+	flag.Var(&maxRecvSize, "max-recv-size", "Largest request message the server accepts, e.g. 64M, 4M by default")
+	flag.Var(&maxSendSize, "max-send-size", "Largest response message the server sends, unlimited by default")
+	flag.StringVar(&metricsAddr, "metrics-addr", metricsAddr, "Address to expose prometheus /metrics on, e.g. :9090, disabled when empty")
+	flag.StringVar(&tlsCfg.CertFile, "tls-cert", tlsCfg.CertFile, "TLS server certificate file, enables TLS")
+	flag.StringVar(&tlsCfg.KeyFile, "tls-key", tlsCfg.KeyFile, "TLS server key file")
+	flag.StringVar(&tlsCfg.ClientCAFile, "tls-client-ca", tlsCfg.ClientCAFile, "CA file to verify client certificates, enables mTLS")
+	flag.StringVar(&tlsCfg.GenerateDir, "tls-gen-dir", tlsCfg.GenerateDir, "Generate a throwaway CA, server and client certs into this dir and serve TLS with them")
+	flag.StringVar(&tlsCfg.Hosts, "tls-hosts", tlsCfg.Hosts, "Comma separated extra host names or IPs of the generated server cert")
+	flag.StringVar(&tracing.Exporter, "trace-exporter", tracing.Exporter, "OpenTelemetry span exporter: none, stdout, file or otlp")
+	flag.StringVar(&tracing.File, "trace-file", tracing.File, "JSON file the file trace exporter appends spans to")
+	flag.StringVar(&tracing.OTLPEndpoint, "trace-otlp-endpoint", tracing.OTLPEndpoint, "OTLP/gRPC endpoint of the otlp trace exporter")
+	flag.BoolVar(&tracing.MessageEvents, "trace-message-events", tracing.MessageEvents, "Record every stream message as a span event")
 	flag.Parse()
-	server_start(port)
+	if err := cfg.Runtime.validate(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if err := cfg.Compression.validate(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	if err := tlsCfg.validate(); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+	var err error
+	if cfg.ReplicaRuntime, err = parseReplicaRuntime(replicaRuntime, cfg.Runtime); err != nil {
+		fmt.Println(err)
+		os.Exit(1)
+	}
+
+	var opts []grpc.ServerOption
+	if maxRecvSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(int(maxRecvSize)))
+		zstdCodec.setMaxRecvSize(int(maxRecvSize))
+	}
+	cfg.MaxPayloadSize = defaultMaxPayloadSize
+	if maxSendSize > 0 {
+		opts = append(opts, grpc.MaxSendMsgSize(int(maxSendSize)))
+		cfg.MaxPayloadSize = int(maxSendSize)
+	}
+	if tlsCfg.enabled() {
+		creds, err := tlsCfg.serverCredentials()
+		if err != nil {
+			fmt.Printf("failed to setup TLS: %v\n", err)
+			os.Exit(1)
+		}
+		opts = append(opts, grpc.Creds(creds))
+		if cfg.Gateway {
+			// the gateway would have to be a TLS client of its own server, keep it for plaintext only
+			fmt.Println("REST/JSON gateway is disabled with TLS")
+			cfg.Gateway = false
+		}
+	}
+	traceHandler, upstreamTraceHandler, shutdownTracing, err := tracing.setup("grpc-stream-demo-server")
+	if err != nil {
+		fmt.Printf("failed to setup tracing: %v\n", err)
+		os.Exit(1)
+	}
+	defer shutdownTracing()
+	if traceHandler != nil {
+		opts = append(opts, grpc.StatsHandler(traceHandler))
+	}
+	if metricsAddr != "" {
+		metrics := newMetricsHandler()
+		opts = append(opts, grpc.StatsHandler(metrics))
+		go metrics.serve(metricsAddr)
+	}
+	var upstream *grpc.ClientConn
+	if cfg.Upstream != "" {
+		if upstream, err = dialUpstream(cfg.Upstream, upstreamTraceHandler); err != nil {
+			fmt.Printf("failed to dial upstream: %v\n", err)
+			os.Exit(1)
+		}
+		defer upstream.Close()
+	}
+	server_start(cfg, upstream, opts...)
 }
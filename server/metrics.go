@@ -0,0 +1,168 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+// metricsHandler is a grpc stats.Handler recording prometheus metrics of every rpc and connection
+type metricsHandler struct {
+	registry *prometheus.Registry
+
+	started       *prometheus.CounterVec
+	handled       *prometheus.CounterVec
+	handling      *prometheus.HistogramVec
+	msgReceived   *prometheus.CounterVec
+	msgSent       *prometheus.CounterVec
+	bytesReceived *prometheus.CounterVec
+	bytesSent     *prometheus.CounterVec
+	activeStreams *prometheus.GaugeVec
+	connections   prometheus.Gauge
+	connsTotal    prometheus.Counter
+}
+
+var rpcLabels = []string{"grpc_type", "grpc_service", "grpc_method"}
+
+func newMetricsHandler() *metricsHandler {
+	h := &metricsHandler{
+		registry: prometheus.NewRegistry(),
+		started: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_started_total",
+			Help: "Total number of RPCs started on the server.",
+		}, rpcLabels),
+		handled: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_handled_total",
+			Help: "Total number of RPCs completed on the server, regardless of success or failure.",
+		}, append(rpcLabels, "grpc_code")),
+		handling: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "grpc_server_handling_seconds",
+			Help:    "Histogram of response latency (seconds) of RPCs handled by the server.",
+			Buckets: prometheus.ExponentialBuckets(0.0005, 2, 16),
+		}, rpcLabels),
+		msgReceived: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_msg_received_total",
+			Help: "Total number of stream messages received from the client.",
+		}, rpcLabels),
+		msgSent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_msg_sent_total",
+			Help: "Total number of stream messages sent by the server.",
+		}, rpcLabels),
+		bytesReceived: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_received_bytes_total",
+			Help: "Total wire bytes of messages received, including the 5 bytes grpc frame header.",
+		}, rpcLabels),
+		bytesSent: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "grpc_server_sent_bytes_total",
+			Help: "Total wire bytes of messages sent, including the 5 bytes grpc frame header.",
+		}, rpcLabels),
+		activeStreams: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "grpc_server_active_streams",
+			Help: "Number of RPCs in flight on the server.",
+		}, rpcLabels),
+		connections: prometheus.NewGauge(prometheus.GaugeOpts{
+			Name: "grpc_server_connections",
+			Help: "Number of open transport connections.",
+		}),
+		connsTotal: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: "grpc_server_connections_total",
+			Help: "Total number of transport connections accepted.",
+		}),
+	}
+	h.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		h.started, h.handled, h.handling,
+		h.msgReceived, h.msgSent, h.bytesReceived, h.bytesSent,
+		h.activeStreams, h.connections, h.connsTotal,
+	)
+	return h
+}
+
+// serve exposes /metrics on addr
+func (h *metricsHandler) serve(addr string) {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(h.registry, promhttp.HandlerOpts{}))
+	fmt.Printf("serving metrics on %s/metrics\n", addr)
+	if err := http.ListenAndServe(addr, mux); err != nil {
+		fmt.Printf("failed to serve metrics: %v\n", err)
+	}
+}
+
+type rpcTagKey struct{}
+
+type rpcTag struct {
+	service string
+	method  string
+	// grpc type is only known at stats.Begin
+	rpcType string
+	start   time.Time
+}
+
+func (t *rpcTag) labels() []string {
+	return []string{t.rpcType, t.service, t.method}
+}
+
+func rpcType(begin *stats.Begin) string {
+	switch {
+	case begin.IsClientStream && begin.IsServerStream:
+		return "bidi_stream"
+	case begin.IsClientStream:
+		return "client_stream"
+	case begin.IsServerStream:
+		return "server_stream"
+	}
+	return "unary"
+}
+
+func (h *metricsHandler) TagRPC(ctx context.Context, info *stats.RPCTagInfo) context.Context {
+	service, method, _ := strings.Cut(strings.TrimPrefix(info.FullMethodName, "/"), "/")
+	return context.WithValue(ctx, rpcTagKey{}, &rpcTag{service: service, method: method})
+}
+
+func (h *metricsHandler) HandleRPC(ctx context.Context, s stats.RPCStats) {
+	tag, ok := ctx.Value(rpcTagKey{}).(*rpcTag)
+	if !ok {
+		return
+	}
+	switch s := s.(type) {
+	case *stats.Begin:
+		tag.rpcType = rpcType(s)
+		tag.start = s.BeginTime
+		h.started.WithLabelValues(tag.labels()...).Inc()
+		h.activeStreams.WithLabelValues(tag.labels()...).Inc()
+	case *stats.InPayload:
+		h.msgReceived.WithLabelValues(tag.labels()...).Inc()
+		h.bytesReceived.WithLabelValues(tag.labels()...).Add(float64(s.WireLength))
+	case *stats.OutPayload:
+		h.msgSent.WithLabelValues(tag.labels()...).Inc()
+		h.bytesSent.WithLabelValues(tag.labels()...).Add(float64(s.WireLength))
+	case *stats.End:
+		code := status.Code(s.Error)
+		h.handled.WithLabelValues(append(tag.labels(), code.String())...).Inc()
+		h.handling.WithLabelValues(tag.labels()...).Observe(s.EndTime.Sub(tag.start).Seconds())
+		h.activeStreams.WithLabelValues(tag.labels()...).Dec()
+	}
+}
+
+func (h *metricsHandler) TagConn(ctx context.Context, info *stats.ConnTagInfo) context.Context {
+	return ctx
+}
+
+func (h *metricsHandler) HandleConn(ctx context.Context, s stats.ConnStats) {
+	switch s.(type) {
+	case *stats.ConnBegin:
+		h.connections.Inc()
+		h.connsTotal.Inc()
+	case *stats.ConnEnd:
+		h.connections.Dec()
+	}
+}
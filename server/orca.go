@@ -0,0 +1,64 @@
+package main
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/orca"
+)
+
+// orcaReporter sends ORCA load reports for weighted round robin clients, per call in the
+// endpoint-load-metrics-bin trailer and out of band on the OpenRcaService stream.
+// It reports the call rate and a utilization derived from the runtime config.
+type orcaReporter struct {
+	settings *runtimeSettings
+	recorder orca.ServerMetricsRecorder
+	calls    atomic.Int64
+}
+
+func newOrcaReporter(settings *runtimeSettings) *orcaReporter {
+	return &orcaReporter{settings: settings, recorder: orca.NewServerMetricsRecorder()}
+}
+
+// serverOptions must come before the other interceptors, the orca ones provide the per-call recorder
+func (r *orcaReporter) serverOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		orca.CallMetricsServerOption(r.recorder),
+		grpc.ChainUnaryInterceptor(r.unaryInterceptor),
+		grpc.ChainStreamInterceptor(r.streamInterceptor),
+	}
+}
+
+// The per-call report is only sent by calls that asked for their recorder, which merges in the server metrics.
+
+func (r *orcaReporter) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	r.calls.Add(1)
+	orca.CallMetricsRecorderFromContext(ctx)
+	return handler(ctx, req)
+}
+
+func (r *orcaReporter) streamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	r.calls.Add(1)
+	orca.CallMetricsRecorderFromContext(ss.Context())
+	return handler(srv, ss)
+}
+
+// run updates the reported metrics every second until stop is closed
+func (r *orcaReporter) run(stop <-chan struct{}) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	last := time.Now()
+	for {
+		select {
+		case <-stop:
+			return
+		case now := <-ticker.C:
+			rps := float64(r.calls.Swap(0)) / now.Sub(last).Seconds()
+			last = now
+			r.recorder.SetQPS(rps)
+			r.recorder.SetCPUUtilization(r.settings.get().Utilization * rps / 100)
+		}
+	}
+}
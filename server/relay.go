@@ -0,0 +1,260 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"server/message/pb"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/resolver"
+	"google.golang.org/grpc/resolver/manual"
+	"google.golang.org/grpc/stats"
+	"google.golang.org/grpc/status"
+)
+
+// relayHopKey is appended to the request metadata by every relay, with its identity, so the last
+// server sees the whole path of the call
+const relayHopKey = "x-relay-hop"
+
+// maxRelayHops stops a call going round a loop of relays
+const maxRelayHops = 16
+
+// relayScheme resolves the comma separated -upstream list
+const relayScheme = "relay"
+
+// dialUpstream connects to the -upstream servers: a grpc target such as dns:///host:port is used as is,
+// anything else is a comma separated list balanced round robin. traceHandler, when set, traces the
+// upstream calls as children of the relayed ones and propagates their trace context.
+func dialUpstream(upstream string, traceHandler stats.Handler) (*grpc.ClientConn, error) {
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithDefaultServiceConfig(`{"loadBalancingConfig": [{"round_robin": {}}]}`),
+	}
+	if traceHandler != nil {
+		opts = append(opts, grpc.WithStatsHandler(traceHandler))
+	}
+	target := upstream
+	if !strings.Contains(upstream, "://") {
+		state := resolver.State{}
+		for _, addr := range strings.Split(upstream, ",") {
+			if addr = strings.TrimSpace(addr); addr != "" {
+				a := resolver.Address{Addr: addr}
+				state.Addresses = append(state.Addresses, a)
+				state.Endpoints = append(state.Endpoints, resolver.Endpoint{Addresses: []resolver.Address{a}})
+			}
+		}
+		if len(state.Addresses) == 0 {
+			return nil, fmt.Errorf("no upstream in %q", upstream)
+		}
+		r := manual.NewBuilderWithScheme(relayScheme)
+		r.InitialState(state)
+		opts = append(opts, grpc.WithResolvers(r))
+		target = relayScheme + ":///upstream"
+	}
+	return grpc.NewClient(target, opts...)
+}
+
+// relayServer forwards every StreamingService rpc to the upstream servers instead of answering it.
+// The request metadata, x-fault-* included, goes upstream with a hop marker, and the upstream
+// responses, header, trailer and status come back as they are. The runtime config of the relay
+// still applies to its hop: the error rate fails calls and the delay holds back every stream response.
+type relayServer struct {
+	pb.UnimplementedStreamingServiceServer
+	client   pb.StreamingServiceClient
+	identity string
+	settings *runtimeSettings
+}
+
+// upstreamContext copies the request metadata into the context of the upstream call. grpc sets the
+// transport headers again, and a traced relay injects its own span as the parent of the next hop.
+func (s *relayServer) upstreamContext(ctx context.Context, method string) (context.Context, error) {
+	in, _ := metadata.FromIncomingContext(ctx)
+	out := metadata.MD{}
+	for k, v := range in {
+		// grpc-previous-rpc-attempts is the one grpc header grpc-go takes from the metadata, the last
+		// server needs it to count the retried and hedged attempts of x-fault-attempts
+		if k != previousAttemptsKey && (strings.HasPrefix(k, ":") || strings.HasPrefix(k, "grpc-") || k == "content-type" || k == "user-agent" || k == "te") {
+			continue
+		}
+		out[k] = append([]string(nil), v...)
+	}
+	hops := len(out.Get(relayHopKey))
+	if hops >= maxRelayHops {
+		return nil, status.Errorf(codes.FailedPrecondition, "%s went through %d relays already: %s", method, hops, strings.Join(out.Get(relayHopKey), ", "))
+	}
+	out.Append(relayHopKey, s.identity)
+	fmt.Printf("relaying %s upstream, hop %d\n", method, hops+1)
+	return metadata.NewOutgoingContext(ctx, out), nil
+}
+
+// upstreamStream is the common part of the upstream stream clients
+type upstreamStream interface {
+	Header() (metadata.MD, error)
+	Trailer() metadata.MD
+}
+
+// headerForwarder copies the upstream response header once, before the first response goes downstream
+type headerForwarder struct {
+	up   upstreamStream
+	down grpc.ServerStream
+	done bool
+}
+
+func (h *headerForwarder) forward() {
+	if h.done {
+		return
+	}
+	h.done = true
+	// the header is received by now, Header does not block; Trailers-Only upstream answers have none
+	if md, err := h.up.Header(); err == nil && len(md) > 0 {
+		h.down.SetHeader(md)
+	}
+}
+
+func (s *relayServer) UnaryRPC(ctx context.Context, req *pb.UnaryRequest) (*pb.UnaryResponse, error) {
+	upCtx, err := s.upstreamContext(ctx, "UnaryRPC")
+	if err != nil {
+		return nil, err
+	}
+	var header, trailer metadata.MD
+	resp, err := s.client.UnaryRPC(upCtx, req, grpc.Header(&header), grpc.Trailer(&trailer))
+	if len(header) > 0 {
+		grpc.SetHeader(ctx, header)
+	}
+	grpc.SetTrailer(ctx, trailer)
+	return resp, err
+}
+
+func (s *relayServer) ClientStreamRPC(stream pb.StreamingService_ClientStreamRPCServer) error {
+	upCtx, err := s.upstreamContext(stream.Context(), "ClientStreamRPC")
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(upCtx)
+	defer cancel()
+	up, err := s.client.ClientStreamRPC(ctx)
+	if err != nil {
+		return err
+	}
+	requests := recvLoop(stream.Context(), stream.Recv)
+	for {
+		req, err := nextRequest(stream.Context(), requests)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return err
+		}
+		if err := up.Send(req); err != nil {
+			// the upstream status comes with CloseAndRecv
+			break
+		}
+	}
+	resp, err := up.CloseAndRecv()
+	(&headerForwarder{up: up, down: stream}).forward()
+	stream.SetTrailer(up.Trailer())
+	if err != nil {
+		return err
+	}
+	return stream.SendAndClose(resp)
+}
+
+func (s *relayServer) ServerStreamRPC(req *pb.ServerStreamRequest, stream pb.StreamingService_ServerStreamRPCServer) error {
+	upCtx, err := s.upstreamContext(stream.Context(), "ServerStreamRPC")
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(upCtx)
+	defer cancel()
+	up, err := s.client.ServerStreamRPC(ctx, req)
+	if err != nil {
+		return err
+	}
+	header := &headerForwarder{up: up, down: stream}
+	for {
+		resp, err := up.Recv()
+		header.forward()
+		if err != nil {
+			stream.SetTrailer(up.Trailer())
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if err := sleep(stream.Context(), s.settings.get().delay()); err != nil {
+			return err
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
+
+func (s *relayServer) BidirectionalStreamRPC(stream pb.StreamingService_BidirectionalStreamRPCServer) error {
+	upCtx, err := s.upstreamContext(stream.Context(), "BidirectionalStreamRPC")
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithCancel(upCtx)
+	defer cancel()
+	up, err := s.client.BidirectionalStreamRPC(ctx)
+	if err != nil {
+		return err
+	}
+	// requests are pumped upstream on their own, a failure on either side cancels the upstream call
+	requests := recvLoop(stream.Context(), stream.Recv)
+	var pump sync.WaitGroup
+	pump.Add(1)
+	go func() {
+		defer pump.Done()
+		for {
+			req, err := nextRequest(ctx, requests)
+			if err == io.EOF {
+				up.CloseSend()
+				return
+			}
+			if err != nil {
+				cancel()
+				return
+			}
+			if err := up.Send(req); err != nil {
+				// the upstream status comes with the next Recv below
+				cancel()
+				return
+			}
+		}
+	}()
+	err = s.forwardBidiResponses(up, stream)
+	// the upstream may end before the client stops sending, canceling it stops the pump waiting for a request
+	cancel()
+	pump.Wait()
+	return err
+}
+
+// forwardBidiResponses sends the upstream responses downstream, it returns the upstream status
+func (s *relayServer) forwardBidiResponses(up pb.StreamingService_BidirectionalStreamRPCClient, stream pb.StreamingService_BidirectionalStreamRPCServer) error {
+	header := &headerForwarder{up: up, down: stream}
+	for {
+		resp, err := up.Recv()
+		header.forward()
+		if err != nil {
+			stream.SetTrailer(up.Trailer())
+			if err == io.EOF {
+				return nil
+			}
+			return err
+		}
+		if err := sleep(stream.Context(), s.settings.get().delay()); err != nil {
+			return err
+		}
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+}
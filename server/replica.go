@@ -0,0 +1,474 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"server/message/pb"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/soheilhy/cmux"
+	"google.golang.org/grpc"
+	grpcadmin "google.golang.org/grpc/admin"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/orca"
+	"google.golang.org/grpc/reflection"
+)
+
+// replicaKey is the header and trailer naming the replica that served the call. The header is
+// left out of x-fault-trailers-only calls, which would no longer be Trailers-Only and not retried.
+const replicaKey = "x-replica"
+
+// replica states, as listed by the admin api
+const (
+	replicaRunning  = "running"
+	replicaStopping = "stopping"
+	replicaStopped  = "stopped"
+)
+
+// replica is one StreamingServer of the cluster. Its identity, address and runtime settings
+// outlive a kill, every start serves them with a new grpc server.
+type replica struct {
+	index    int
+	identity string
+	network  string
+	address  string
+	cfg      serverConfig
+	opts     []grpc.ServerOption
+	settings *runtimeSettings
+	// upstream makes the replica a relay, nil answers locally
+	upstream *grpc.ClientConn
+
+	mu    sync.Mutex
+	state string
+	run   *replicaRun
+}
+
+// replicaRun is a replica between a start and the next stop
+type replicaRun struct {
+	server     *grpc.Server
+	health     *health.Server
+	registry   *streamRegistry
+	listener   net.Listener
+	httpServer *http.Server
+	started    time.Time
+	// stopping is closed when the run is stopped on purpose, so the serve errors that follow are expected
+	stopping chan struct{}
+	// stopped is closed once the run is stopped and its replica can start again
+	stopped  chan struct{}
+	cleanups []func()
+
+	mu sync.Mutex
+	// services are the names set on the health server, which can't list them itself
+	services map[string]bool
+}
+
+// replicaInfo is the admin api view of a replica
+type replicaInfo struct {
+	Index    int           `json:"index"`
+	Identity string        `json:"identity"`
+	Address  string        `json:"address"`
+	State    string        `json:"state"`
+	Uptime   string        `json:"uptime,omitempty"`
+	Inflight int           `json:"inflight"`
+	Runtime  runtimeConfig `json:"runtime"`
+}
+
+// target is the address clients dial, e.g. in the -backends list of the client
+func (r *replica) target() string {
+	if r.network == "unix" {
+		return "unix:" + r.address
+	}
+	if strings.HasPrefix(r.address, ":") {
+		return "localhost" + r.address
+	}
+	return r.address
+}
+
+func (r *replica) info() replicaInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	info := replicaInfo{Index: r.index, Identity: r.identity, Address: r.target(), State: r.state, Runtime: r.settings.get()}
+	if r.run != nil {
+		info.Uptime = time.Since(r.run.started).Round(time.Second).String()
+		info.Inflight = len(r.run.registry.inflight())
+	}
+	return info
+}
+
+// current returns the run of the replica, nil when it is stopped
+func (r *replica) current() *replicaRun {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.run
+}
+
+// start serves the replica on its address, it fails when the replica is not stopped
+func (r *replica) start() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.state != replicaStopped {
+		return fmt.Errorf("replica %s is %s", r.identity, r.state)
+	}
+	if r.network == "unix" {
+		// a socket left by a killed process would fail the listen
+		if err := os.Remove(r.address); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	l, err := net.Listen(r.network, r.address)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %v", err)
+	}
+	run, err := r.newRun(l)
+	if err != nil {
+		l.Close()
+		return err
+	}
+	r.run = run
+	r.state = replicaRunning
+	go r.serve(run)
+	fmt.Printf("replica %s serving on %s\n", r.identity, r.target())
+	return nil
+}
+
+// newRun builds the grpc server of a start, with a registry, fault injector and orca reporter of its own
+func (r *replica) newRun(l net.Listener) (*replicaRun, error) {
+	run := &replicaRun{
+		registry: newStreamRegistry(),
+		listener: l,
+		started:  time.Now(),
+		stopping: make(chan struct{}),
+		stopped:  make(chan struct{}),
+		services: map[string]bool{"": true, streamingServiceName: true},
+	}
+	faults := &faultInjector{settings: r.settings, relay: r.upstream != nil}
+	compression := &compressor{cfg: r.cfg.Compression}
+	opts := slices.Clip(r.opts)
+	var orcaReports *orcaReporter
+	if r.cfg.Orca {
+		orcaReports = newOrcaReporter(r.settings)
+		opts = append(opts, orcaReports.serverOptions()...)
+	}
+	// the compressor wraps the stream before the other demo interceptors, so they never see its prepared messages
+	opts = append(opts,
+		grpc.StatsHandler(compression),
+		grpc.ChainUnaryInterceptor(r.unaryInterceptor, compression.unaryInterceptor, run.registry.unaryInterceptor, faults.unaryInterceptor),
+		grpc.ChainStreamInterceptor(r.streamInterceptor, compression.streamInterceptor, run.registry.streamInterceptor, faults.streamInterceptor),
+	)
+	s := grpc.NewServer(opts...)
+	run.server = s
+	if r.upstream != nil {
+		pb.RegisterStreamingServiceServer(s, &relayServer{client: pb.NewStreamingServiceClient(r.upstream), identity: r.identity, settings: r.settings})
+	} else {
+		pb.RegisterStreamingServiceServer(s, &StreamingServer{settings: r.settings, identity: r.identity, maxPayloadSize: r.cfg.MaxPayloadSize})
+	}
+	pb.RegisterTypesServiceServer(s, &TypesServer{})
+	run.health = health.NewServer()
+	run.health.SetServingStatus(streamingServiceName, healthpb.HealthCheckResponse_SERVING)
+	healthpb.RegisterHealthServer(s, run.health)
+	if r.cfg.Reflection {
+		// reflection lists the services of s when asked and resolves their descriptors from
+		// the global registry, so every service registered on s is covered
+		reflection.Register(s)
+	}
+	if orcaReports != nil {
+		if err := orca.Register(s, orca.ServiceOptions{ServerMetricsProvider: orcaReports.recorder}); err != nil {
+			s.Stop()
+			return nil, fmt.Errorf("failed to register the orca service: %v", err)
+		}
+		stopOrca := make(chan struct{})
+		go orcaReports.run(stopOrca)
+		run.cleanups = append(run.cleanups, func() { close(stopOrca) })
+	}
+	if r.cfg.Channelz {
+		cleanup, err := grpcadmin.Register(s)
+		if err != nil {
+			s.Stop()
+			run.close()
+			return nil, fmt.Errorf("failed to register admin services: %v", err)
+		}
+		run.cleanups = append(run.cleanups, cleanup)
+	}
+	if r.cfg.Gateway {
+		// the gateway is built before the run is visible, so a stop always finds its http server
+		handler, closeGateway, err := newGatewayHandler(s)
+		if err != nil {
+			s.Stop()
+			run.close()
+			return nil, fmt.Errorf("failed to create gateway: %v", err)
+		}
+		run.httpServer = &http.Server{Handler: handler}
+		run.cleanups = append(run.cleanups, closeGateway)
+	}
+	return run, nil
+}
+
+// serve blocks until the run is stopped, an unexpected error stops the replica
+func (r *replica) serve(run *replicaRun) {
+	var err error
+	if !r.cfg.Gateway {
+		err = run.server.Serve(run.listener)
+	} else {
+		// server mux for handle http&grpc req on the same port: HTTP/2 requests with a grpc content-type
+		// go to the grpc server, everything else, HTTP/1 or h2c, goes to the gateway
+		m := cmux.New(run.listener)
+		grpcL := m.MatchWithWriters(cmux.HTTP2MatchHeaderFieldPrefixSendSettings("content-type", "application/grpc"))
+		httpL := m.Match(cmux.Any())
+		go func() {
+			if err := run.httpServer.Serve(httpL); err != nil && err != http.ErrServerClosed && err != cmux.ErrServerClosed {
+				fmt.Printf("replica %s failed to serve http: %v\n", r.identity, err)
+			}
+		}()
+		go func() {
+			if err := run.server.Serve(grpcL); err != nil {
+				fmt.Printf("replica %s failed to serve grpc: %v\n", r.identity, err)
+			}
+		}()
+		err = m.Serve()
+	}
+	select {
+	case <-run.stopping:
+	default:
+		fmt.Printf("replica %s failed to serve: %v\n", r.identity, err)
+		r.stopRun(run, false)
+	}
+}
+
+// stop drains the in-flight rpcs of the replica up to the drain timeout when graceful,
+// or cuts them and closes its connections at once, as a crash would
+func (r *replica) stop(graceful bool) error {
+	run, err := r.beginStop()
+	if err != nil {
+		return err
+	}
+	r.endStop(run, graceful)
+	return nil
+}
+
+// stopInBackground stops the replica like stop without waiting for the drain, then runs once it is
+// stopped, e.g. to start it again. It fails at once when the replica is not running.
+func (r *replica) stopInBackground(graceful bool, then func()) error {
+	run, err := r.beginStop()
+	if err != nil {
+		return err
+	}
+	go func() {
+		r.endStop(run, graceful)
+		if then != nil {
+			then()
+		}
+	}()
+	return nil
+}
+
+// beginStop marks the current run as stopping
+func (r *replica) beginStop() (*replicaRun, error) {
+	run := r.current()
+	if run == nil {
+		return nil, fmt.Errorf("replica %s is stopped", r.identity)
+	}
+	if !r.markStopping(run) {
+		return nil, fmt.Errorf("replica %s is already stopping", r.identity)
+	}
+	return run, nil
+}
+
+// stopRun stops run if it is still the current one, and reports whether it did
+func (r *replica) stopRun(run *replicaRun, graceful bool) bool {
+	if !r.markStopping(run) {
+		return false
+	}
+	r.endStop(run, graceful)
+	return true
+}
+
+// markStopping moves run from running to stopping, it reports false when run is not running anymore
+func (r *replica) markStopping(run *replicaRun) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.run != run || r.state != replicaRunning {
+		return false
+	}
+	r.state = replicaStopping
+	close(run.stopping)
+	return true
+}
+
+// endStop stops a run marked stopping
+func (r *replica) endStop(run *replicaRun, graceful bool) {
+	if graceful {
+		fmt.Printf("replica %s stopping gracefully\n", r.identity)
+		gracefulShutdown(run.server, run.health, run.registry, run.httpServer, r.cfg.DrainTimeout)
+	} else {
+		run.health.Shutdown()
+		cut := run.registry.forceClose()
+		run.server.Stop()
+		if run.httpServer != nil {
+			run.httpServer.Close()
+		}
+		fmt.Printf("replica %s killed, %d in-flight rpcs cut\n", r.identity, len(cut))
+	}
+	run.listener.Close()
+	run.close()
+
+	r.mu.Lock()
+	r.run = nil
+	r.state = replicaStopped
+	r.mu.Unlock()
+	close(run.stopped)
+}
+
+func (run *replicaRun) close() {
+	for _, cleanup := range run.cleanups {
+		cleanup()
+	}
+}
+
+// setIdentity names the replica in the headers and trailers of every call
+func (r *replica) setIdentity(ctx context.Context, setHeader func(metadata.MD) error, setTrailer func(metadata.MD)) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	identity := metadata.Pairs(replicaKey, r.identity)
+	if mdValue(md, faultTrailersOnlyKey) != "true" {
+		setHeader(identity)
+	}
+	setTrailer(identity)
+}
+
+func (r *replica) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	r.setIdentity(ctx, func(md metadata.MD) error { return grpc.SetHeader(ctx, md) }, func(md metadata.MD) { grpc.SetTrailer(ctx, md) })
+	return handler(ctx, req)
+}
+
+func (r *replica) streamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	r.setIdentity(ss.Context(), ss.SetHeader, ss.SetTrailer)
+	return handler(srv, ss)
+}
+
+// cluster is the set of replicas started by -replicas, a single one by default
+type cluster struct {
+	replicas []*replica
+}
+
+// newCluster lays out the replicas: replica i listens on port+i, or on <socket-dir>/replica-<i>.sock,
+// and is called <name>-<i>, or just <name> when it is alone
+func newCluster(cfg serverConfig, upstream *grpc.ClientConn, opts []grpc.ServerOption) (*cluster, error) {
+	if cfg.Replicas < 1 {
+		return nil, fmt.Errorf("replicas must be at least 1: %d", cfg.Replicas)
+	}
+	if len(cfg.ReplicaRuntime) > cfg.Replicas {
+		return nil, fmt.Errorf("%d replica runtime configs for %d replicas", len(cfg.ReplicaRuntime), cfg.Replicas)
+	}
+	port, err := strconv.Atoi(cfg.Port)
+	if err != nil && cfg.SocketDir == "" {
+		return nil, fmt.Errorf("invalid port %q", cfg.Port)
+	}
+	c := &cluster{}
+	for i := 0; i < cfg.Replicas; i++ {
+		r := &replica{index: i, identity: cfg.ReplicaName, network: "tcp", address: fmt.Sprintf(":%d", port+i), cfg: cfg, opts: opts, upstream: upstream, state: replicaStopped}
+		if cfg.Replicas > 1 {
+			r.identity = fmt.Sprintf("%s-%d", cfg.ReplicaName, i)
+		}
+		if cfg.SocketDir != "" {
+			r.network = "unix"
+			r.address = filepath.Join(cfg.SocketDir, fmt.Sprintf("replica-%d.sock", i))
+		}
+		runtime := cfg.Runtime
+		if i < len(cfg.ReplicaRuntime) {
+			runtime = cfg.ReplicaRuntime[i]
+		}
+		r.settings = newRuntimeSettings(runtime)
+		c.replicas = append(c.replicas, r)
+	}
+	return c, nil
+}
+
+// replica returns replica i, or nil
+func (c *cluster) replica(i int) *replica {
+	if i < 0 || i >= len(c.replicas) {
+		return nil
+	}
+	return c.replicas[i]
+}
+
+// start starts every replica, the ones already started are stopped again on a failure
+func (c *cluster) start() error {
+	for _, r := range c.replicas {
+		if err := r.start(); err != nil {
+			c.stop(false)
+			return fmt.Errorf("replica %s: %v", r.identity, err)
+		}
+	}
+	if len(c.replicas) > 1 {
+		targets := make([]string, 0, len(c.replicas))
+		for _, r := range c.replicas {
+			targets = append(targets, r.target())
+		}
+		fmt.Printf("client flag for the %d replicas: -backends %s\n", len(c.replicas), strings.Join(targets, ","))
+	}
+	return nil
+}
+
+// stop stops the running replicas concurrently, and waits for the ones already stopping, e.g. draining
+// after an admin kill, to be stopped too
+func (c *cluster) stop(graceful bool) {
+	var wg sync.WaitGroup
+	for _, r := range c.replicas {
+		run := r.current()
+		if run == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(r *replica) {
+			defer wg.Done()
+			if !r.stopRun(run, graceful) {
+				<-run.stopped
+			}
+		}(r)
+	}
+	wg.Wait()
+}
+
+// parseReplicaRuntime reads the -replica-runtime JSON list, inline or from @file. Entry i is decoded
+// over the runtime flags and becomes the initial runtime config of replica i.
+func parseReplicaRuntime(spec string, base runtimeConfig) ([]runtimeConfig, error) {
+	if spec == "" {
+		return nil, nil
+	}
+	data := []byte(spec)
+	if path, ok := strings.CutPrefix(spec, "@"); ok {
+		var err error
+		if data, err = os.ReadFile(path); err != nil {
+			return nil, err
+		}
+	}
+	var entries []json.RawMessage
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("invalid replica runtime: %v", err)
+	}
+	configs := make([]runtimeConfig, 0, len(entries))
+	for i, entry := range entries {
+		cfg := base
+		decoder := json.NewDecoder(strings.NewReader(string(entry)))
+		decoder.DisallowUnknownFields()
+		if err := decoder.Decode(&cfg); err != nil {
+			return nil, fmt.Errorf("invalid runtime of replica %d: %v", i, err)
+		}
+		if err := cfg.validate(); err != nil {
+			return nil, fmt.Errorf("invalid runtime of replica %d: %v", i, err)
+		}
+		configs = append(configs, cfg)
+	}
+	return configs, nil
+}
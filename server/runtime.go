@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+)
+
+// runtimeConfig is the traffic shape of the server, it can be changed at runtime through the admin api.
+// Request fields, such as response_count or interval_ms, still win over it.
+type runtimeConfig struct {
+	// DelayMs is the wait time between two stream responses
+	DelayMs int `json:"delay_ms"`
+	// ErrorRate is the ratio, from 0 to 1, of rpcs failed with ErrorCode
+	ErrorRate float64 `json:"error_rate"`
+	// ErrorCode is a status code name or number, UNAVAILABLE by default
+	ErrorCode string `json:"error_code"`
+	// StreamLength is the number of ServerStreamRPC responses
+	StreamLength int `json:"stream_length"`
+	// PayloadSize is the size of the payload of every stream response
+	PayloadSize int `json:"payload_size"`
+	// Utilization is the CPU utilization reported by -orca at 100 calls per second, the report grows
+	// with the call rate, so weighted round robin clients weigh the server 1/Utilization
+	Utilization float64 `json:"utilization"`
+}
+
+func (c runtimeConfig) validate() error {
+	if c.DelayMs < 0 {
+		return fmt.Errorf("delay_ms must not be negative")
+	}
+	if c.ErrorRate < 0 || c.ErrorRate > 1 {
+		return fmt.Errorf("error_rate must be between 0 and 1")
+	}
+	if c.ErrorCode != "" {
+		if _, err := parseCode(c.ErrorCode); err != nil {
+			return fmt.Errorf("invalid error_code %q", c.ErrorCode)
+		}
+	}
+	if c.StreamLength < 0 || c.StreamLength > maxResponseCount {
+		return fmt.Errorf("stream_length must be between 0 and %d", maxResponseCount)
+	}
+	if c.PayloadSize < 0 {
+		return fmt.Errorf("payload_size must not be negative")
+	}
+	if c.Utilization < 0 {
+		return fmt.Errorf("utilization must not be negative")
+	}
+	return nil
+}
+
+func (c runtimeConfig) delay() time.Duration {
+	return time.Duration(c.DelayMs) * time.Millisecond
+}
+
+func (c runtimeConfig) errorCode() codes.Code {
+	code, err := parseCode(c.ErrorCode)
+	if err != nil || c.ErrorCode == "" {
+		return codes.Unavailable
+	}
+	return code
+}
+
+// randomError reports whether this rpc is one of the ErrorRate failing ones
+func (c runtimeConfig) randomError() bool {
+	return c.ErrorRate > 0 && rand.Float64() < c.ErrorRate
+}
+
+// runtimeSettings shares the runtimeConfig between the handlers and the admin api
+type runtimeSettings struct {
+	mu  sync.RWMutex
+	cfg runtimeConfig
+}
+
+func newRuntimeSettings(cfg runtimeConfig) *runtimeSettings {
+	return &runtimeSettings{cfg: cfg}
+}
+
+// get returns a copy of the current config, which stays consistent for the rest of the rpc
+func (s *runtimeSettings) get() runtimeConfig {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.cfg
+}
+
+// update replaces the config, it is left untouched when cfg is invalid
+func (s *runtimeSettings) update(cfg runtimeConfig) error {
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.cfg = cfg
+	s.mu.Unlock()
+	return nil
+}
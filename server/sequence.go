@@ -0,0 +1,144 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"hash/crc32"
+	"strings"
+	"time"
+
+	"server/message/pb"
+)
+
+func checksum(text string, payload []byte) uint32 {
+	return crc32.Update(crc32.ChecksumIEEE([]byte(text)), crc32.IEEETable, payload)
+}
+
+func newStreamID() string {
+	b := make([]byte, 8)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// seqSender stamps the messages sent on one stream
+type seqSender struct {
+	streamID string
+	seq      uint64
+}
+
+func newSeqSender() *seqSender {
+	return &seqSender{streamID: newStreamID()}
+}
+
+// next returns the meta of the next message, ack is the meta of the request it answers, if any
+func (s *seqSender) next(text string, payload []byte, ack *pb.MessageMeta) *pb.MessageMeta {
+	s.seq++
+	meta := &pb.MessageMeta{
+		StreamId:       s.streamID,
+		Seq:            s.seq,
+		SentAtUnixNano: time.Now().UnixNano(),
+		Checksum:       checksum(text, payload),
+	}
+	if ack != nil {
+		meta.AckSeq = ack.GetSeq()
+		meta.AckSentAtUnixNano = ack.GetSentAtUnixNano()
+	}
+	return meta
+}
+
+// seqChecker verifies the messages received on one stream: gaps, duplicates, reordering,
+// corruption and a stream id changing midway, and measures their latencies.
+// Messages without meta, e.g. sent through the gateway, are not checked.
+type seqChecker struct {
+	streamID string
+	last     uint64
+	// missing are the seqs skipped so far, a late one is reordered rather than lost
+	missing map[uint64]bool
+
+	received   int
+	duplicates int
+	reordered  int
+	corrupted  int
+	foreign    int
+	oneWay     time.Duration
+	oneWays    int
+	maxOneWay  time.Duration
+	rtt        time.Duration
+	rtts       int
+}
+
+func newSeqChecker() *seqChecker {
+	return &seqChecker{missing: map[uint64]bool{}}
+}
+
+// check records one received message and returns an error describing its anomaly, if any
+func (c *seqChecker) check(meta *pb.MessageMeta, text string, payload []byte) error {
+	if meta == nil {
+		return nil
+	}
+	now := time.Now()
+	c.received++
+	if meta.GetSentAtUnixNano() > 0 {
+		// one-way latency mixes both clocks, it only makes sense on the same host or with synced clocks
+		oneWay := now.Sub(time.Unix(0, meta.GetSentAtUnixNano()))
+		c.oneWay += oneWay
+		c.oneWays++
+		c.maxOneWay = max(c.maxOneWay, oneWay)
+	}
+	if meta.GetAckSentAtUnixNano() > 0 {
+		c.rtt += now.Sub(time.Unix(0, meta.GetAckSentAtUnixNano()))
+		c.rtts++
+	}
+
+	if sum := checksum(text, payload); sum != meta.GetChecksum() {
+		c.corrupted++
+		return fmt.Errorf("seq %d is corrupted, checksum %08x, expected %08x", meta.GetSeq(), sum, meta.GetChecksum())
+	}
+	if c.streamID == "" {
+		c.streamID = meta.GetStreamId()
+	} else if meta.GetStreamId() != c.streamID {
+		c.foreign++
+		return fmt.Errorf("seq %d belongs to stream %s, expected %s", meta.GetSeq(), meta.GetStreamId(), c.streamID)
+	}
+
+	seq := meta.GetSeq()
+	switch {
+	case seq == c.last+1:
+		c.last = seq
+	case seq > c.last+1:
+		for missing := c.last + 1; missing < seq; missing++ {
+			c.missing[missing] = true
+		}
+		err := fmt.Errorf("gap before seq %d, missing %d", seq, seq-c.last-1)
+		c.last = seq
+		return err
+	case c.missing[seq]:
+		delete(c.missing, seq)
+		c.reordered++
+		return fmt.Errorf("seq %d arrived out of order, after seq %d", seq, c.last)
+	default:
+		c.duplicates++
+		return fmt.Errorf("seq %d is a duplicate", seq)
+	}
+	return nil
+}
+
+func (c *seqChecker) String() string {
+	if c.received == 0 {
+		return "no sequenced message"
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d messages, %d lost, %d duplicated, %d reordered, %d corrupted",
+		c.received, len(c.missing), c.duplicates, c.reordered, c.corrupted)
+	if c.foreign > 0 {
+		fmt.Fprintf(&b, ", %d from another stream", c.foreign)
+	}
+	if c.oneWays > 0 {
+		fmt.Fprintf(&b, ", one-way avg %v max %v", (c.oneWay / time.Duration(c.oneWays)).Round(time.Microsecond), c.maxOneWay.Round(time.Microsecond))
+	}
+	if c.rtts > 0 {
+		fmt.Fprintf(&b, ", rtt avg %v", (c.rtt / time.Duration(c.rtts)).Round(time.Microsecond))
+	}
+	return b.String()
+}
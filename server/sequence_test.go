@@ -0,0 +1,101 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"server/message/pb"
+)
+
+// request is a request of a client stream, stamped the way the client stamps it
+type request struct {
+	text    string
+	payload []byte
+	meta    *pb.MessageMeta
+}
+
+func clientRequests(n int) []request {
+	s := newSeqSender()
+	requests := make([]request, n)
+	for i := range requests {
+		text := strings.Repeat("x", i+1)
+		payload := []byte{byte(i)}
+		requests[i] = request{text: text, payload: payload, meta: s.next(text, payload, nil)}
+	}
+	return requests
+}
+
+func TestSeqCheckerRequests(t *testing.T) {
+	tests := []struct {
+		name string
+		// order lists the indexes of the requests in their order of arrival
+		order []int
+		// tampered is the index of a request whose payload changes on the way, -1 for none
+		tampered int
+		want     string
+	}{
+		{"in order", []int{0, 1, 2, 3}, -1, "4 messages, 0 lost, 0 duplicated, 0 reordered, 0 corrupted"},
+		{"lost", []int{0, 3}, -1, "2 messages, 2 lost, 0 duplicated, 0 reordered, 0 corrupted"},
+		{"reordered", []int{0, 2, 1, 3}, -1, "4 messages, 0 lost, 0 duplicated, 1 reordered, 0 corrupted"},
+		{"replayed", []int{0, 1, 1, 2, 0}, -1, "5 messages, 0 lost, 2 duplicated, 0 reordered, 0 corrupted"},
+		{"payload corrupted", []int{0, 1, 2}, 1, "3 messages, 1 lost, 0 duplicated, 0 reordered, 1 corrupted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := clientRequests(4)
+			c := newSeqChecker()
+			for _, i := range tt.order {
+				req := requests[i]
+				if i == tt.tampered {
+					req.payload = []byte{0xff}
+				}
+				c.check(req.meta, req.text, req.payload)
+			}
+			// requests carry no ack, so the summary ends with the one-way latency
+			if got := c.String(); !strings.HasPrefix(got, tt.want+", one-way avg") || strings.Contains(got, "rtt") {
+				t.Errorf("summary %q, want %q and a one-way latency", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSeqCheckerRequestsFromTwoClients(t *testing.T) {
+	first, second := clientRequests(2), clientRequests(2)
+	c := newSeqChecker()
+	if err := c.check(first[0].meta, first[0].text, first[0].payload); err != nil {
+		t.Fatal(err)
+	}
+	err := c.check(second[1].meta, second[1].text, second[1].payload)
+	if err == nil || !strings.Contains(err.Error(), "belongs to stream "+second[1].meta.GetStreamId()) {
+		t.Errorf("request of another stream gave %v", err)
+	}
+	if err := c.check(first[1].meta, first[1].text, first[1].payload); err != nil {
+		t.Errorf("next request of the stream: %v", err)
+	}
+}
+
+func TestSeqSenderResponses(t *testing.T) {
+	requests := clientRequests(2)
+	s := newSeqSender()
+	if s.streamID == requests[0].meta.GetStreamId() || len(s.streamID) != 16 {
+		t.Errorf("response stream id %q, want 16 hex digits of its own", s.streamID)
+	}
+	// a bidirectional stream answers every request
+	for i, req := range requests {
+		meta := s.next("response "+req.text, req.payload, req.meta)
+		if meta.GetSeq() != uint64(i+1) {
+			t.Errorf("response %d has seq %d", i, meta.GetSeq())
+		}
+		if meta.GetAckSeq() != req.meta.GetSeq() || meta.GetAckSentAtUnixNano() != req.meta.GetSentAtUnixNano() {
+			t.Errorf("response %d acks seq %d sent at %d, want %d sent at %d", i,
+				meta.GetAckSeq(), meta.GetAckSentAtUnixNano(), req.meta.GetSeq(), req.meta.GetSentAtUnixNano())
+		}
+		if meta.GetChecksum() != checksum("response "+req.text, req.payload) {
+			t.Errorf("response %d checksum %08x does not cover its text and payload", i, meta.GetChecksum())
+		}
+	}
+	// a client stream may end without any request to acknowledge
+	if meta := s.next("done", nil, nil); meta.GetAckSeq() != 0 || meta.GetAckSentAtUnixNano() != 0 {
+		t.Errorf("response without a request acks seq %d", meta.GetAckSeq())
+	}
+}
@@ -4,21 +4,41 @@ import (
 	"context"
 	"fmt"
 	"io"
-	"net"
-
-	// "net/http"
+	"os"
+	"os/signal"
 	"server/message/pb"
+	"syscall"
 	"time"
 
-	// "github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
 )
 
-var ServerDelay = 10
-
 type StreamingServer struct {
 	pb.UnimplementedStreamingServiceServer
+	settings *runtimeSettings
+	// identity names the replica in every response
+	identity string
+	// maxPayloadSize caps the payload of a response
+	maxPayloadSize int
+}
+
+// Limits of the request fields, so one call can't make the server allocate or send without bound.
+// -max-send-size replaces the default payload limit.
+const (
+	defaultMaxPayloadSize = 16 << 20
+	maxResponseCount      = 10000
+)
+
+// checkResponseCount fails the call when a request asks for too many responses
+func checkResponseCount(field string, count int32) error {
+	if count > maxResponseCount {
+		return status.Errorf(codes.InvalidArgument, "%s %d is over the limit of %d", field, count, maxResponseCount)
+	}
+	return nil
 }
 
 func displayMetadata(ctx context.Context) {
@@ -29,75 +49,229 @@ func displayMetadata(ctx context.Context) {
 		}
 		fmt.Println("}")
 	}
+	if p, ok := peer.FromContext(ctx); ok {
+		if identity := peerIdentity(p.AuthInfo); identity != "" {
+			fmt.Printf("peer: %s, %s\n", p.Addr, identity)
+		}
+	}
+}
+
+// checkSeq logs the anomaly of a received message, if any
+func checkSeq(method string, checker *seqChecker, meta *pb.MessageMeta, text string, payload []byte) {
+	if err := checker.check(meta, text, payload); err != nil {
+		fmt.Printf("%s: %v\n", method, err)
+	}
+}
+
+// logSeq logs the sequence summary of a finished stream
+func logSeq(method string, checker *seqChecker) {
+	if checker.received > 0 {
+		fmt.Printf("%s sequence: %s\n", method, checker)
+	}
 }
 
 func (s *StreamingServer) UnaryRPC(ctx context.Context, req *pb.UnaryRequest) (*pb.UnaryResponse, error) {
 	displayMetadata(ctx)
-	return &pb.UnaryResponse{Response: "Unary RPC response: " + req.GetMessage()}, nil
+	checkSeq("UnaryRPC", newSeqChecker(), req.GetMeta(), req.GetMessage(), req.GetPayload())
+	response := "Unary RPC response: " + req.GetMessage()
+	return &pb.UnaryResponse{Response: response, Payload: req.GetPayload(), Replica: s.identity, Meta: newSeqSender().next(response, req.GetPayload(), req.GetMeta())}, nil
 }
 
 func (s *StreamingServer) ClientStreamRPC(stream pb.StreamingService_ClientStreamRPCServer) error {
 	var messages []string
+	checker := newSeqChecker()
+	defer logSeq("ClientStreamRPC", checker)
+	var last *pb.MessageMeta
+	requests := recvLoop(stream.Context(), stream.Recv)
 	for {
-		req, err := stream.Recv()
+		req, err := nextRequest(stream.Context(), requests)
 		if err == io.EOF {
-			return stream.SendAndClose(&pb.ClientStreamResponse{Response: fmt.Sprintf("Client Stream RPC response: %v", messages)})
+			response := fmt.Sprintf("Client Stream RPC response: %v", messages)
+			return stream.SendAndClose(&pb.ClientStreamResponse{Response: response, Replica: s.identity, Meta: newSeqSender().next(response, nil, last)})
 		}
 		if err != nil {
 			return err
 		}
+		checkSeq("ClientStreamRPC", checker, req.GetMeta(), req.GetMessage(), req.GetPayload())
+		if req.GetMeta() != nil {
+			last = req.GetMeta()
+		}
 		messages = append(messages, req.GetMessage())
 	}
 }
 
+// streamInterval returns the wait time asked by a request, falling back to the runtime delay
+func streamInterval(intervalMs int32, cfg runtimeConfig) time.Duration {
+	if intervalMs == 0 {
+		return cfg.delay()
+	}
+	if intervalMs < 0 {
+		return 0
+	}
+	return time.Duration(intervalMs) * time.Millisecond
+}
+
+// sleep waits d between two responses, it returns early with the status of a canceled or killed call
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return contextStatus(ctx)
+	}
+}
+
+// makePayload builds the payload asked by a request, falling back to the runtime payload size,
+// it fails when the size is over limit
+func makePayload(size int32, cfg runtimeConfig, limit int) ([]byte, error) {
+	if size == 0 {
+		size = int32(cfg.PayloadSize)
+	}
+	if size <= 0 {
+		return nil, nil
+	}
+	if int(size) > limit {
+		return nil, status.Errorf(codes.InvalidArgument, "payload_size %d is over the limit of %d bytes", size, limit)
+	}
+	payload := make([]byte, size)
+	for i := range payload {
+		payload[i] = byte('a' + i%26)
+	}
+	return payload, nil
+}
+
 func (s *StreamingServer) ServerStreamRPC(req *pb.ServerStreamRequest, stream pb.StreamingService_ServerStreamRPCServer) error {
-	for i := 0; i < 3; i++ {
-		if err := stream.Send(&pb.ServerStreamResponse{Response: fmt.Sprintf("Server Stream RPC response %d: %s", i, req.GetMessage())}); err != nil {
+	cfg := s.settings.get()
+	if err := checkResponseCount("response_count", req.GetResponseCount()); err != nil {
+		return err
+	}
+	count := int(req.GetResponseCount())
+	if count <= 0 {
+		count = cfg.StreamLength
+	}
+	interval := streamInterval(req.GetIntervalMs(), cfg)
+	payload, err := makePayload(req.GetPayloadSize(), cfg, s.maxPayloadSize)
+	if err != nil {
+		return err
+	}
+	checkSeq("ServerStreamRPC", newSeqChecker(), req.GetMeta(), req.GetMessage(), req.GetPayload())
+	sender := newSeqSender()
+	for i := 0; i < count; i++ {
+		response := fmt.Sprintf("Server Stream RPC response %d: %s", i, req.GetMessage())
+		// only the first response answers the request, the later ones would add the stream interval to the rtt
+		var ack *pb.MessageMeta
+		if i == 0 {
+			ack = req.GetMeta()
+		}
+		if err := stream.Send(&pb.ServerStreamResponse{Response: response, Payload: payload, Replica: s.identity, Meta: sender.next(response, payload, ack)}); err != nil {
+			return err
+		}
+		if err := sleep(stream.Context(), interval); err != nil {
 			return err
 		}
-		time.Sleep(time.Duration(ServerDelay) * time.Millisecond)
 	}
 	return nil
 }
 
 func (s *StreamingServer) BidirectionalStreamRPC(stream pb.StreamingService_BidirectionalStreamRPCServer) error {
+	checker := newSeqChecker()
+	defer logSeq("BidirectionalStreamRPC", checker)
+	sender := newSeqSender()
+	requests := recvLoop(stream.Context(), stream.Recv)
 	for {
-		req, err := stream.Recv()
+		req, err := nextRequest(stream.Context(), requests)
 		if err == io.EOF {
 			return nil
 		}
 		if err != nil {
 			return err
 		}
-		if err := stream.Send(&pb.BidirectionalStreamResponse{Response: "Bidirectional Stream RPC response: " + req.GetMessage()}); err != nil {
+		checkSeq("BidirectionalStreamRPC", checker, req.GetMeta(), req.GetMessage(), req.GetPayload())
+		// read the settings again for every request, so long streams follow the changes
+		cfg := s.settings.get()
+		if err := checkResponseCount("responses_per_request", req.GetResponsesPerRequest()); err != nil {
 			return err
 		}
-		time.Sleep(time.Duration(ServerDelay) * time.Millisecond)
+		count := int(req.GetResponsesPerRequest())
+		if count <= 0 {
+			count = 1
+		}
+		interval := streamInterval(req.GetIntervalMs(), cfg)
+		payload, err := makePayload(req.GetPayloadSize(), cfg, s.maxPayloadSize)
+		if err != nil {
+			return err
+		}
+		response := "Bidirectional Stream RPC response: " + req.GetMessage()
+		for i := 0; i < count; i++ {
+			var ack *pb.MessageMeta
+			if i == 0 {
+				ack = req.GetMeta()
+			}
+			if err := stream.Send(&pb.BidirectionalStreamResponse{Response: response, Payload: payload, Replica: s.identity, Meta: sender.next(response, payload, ack)}); err != nil {
+				return err
+			}
+			if err := sleep(stream.Context(), interval); err != nil {
+				return err
+			}
+		}
 	}
 }
 
-func server_start(port string) {
-	l, err := net.Listen("tcp", fmt.Sprintf(":%s", port))
+// serverConfig is filled by the server flags
+type serverConfig struct {
+	Port    string
+	Gateway bool
+	// AdminAddr serves the admin api when set
+	AdminAddr string
+	// Reflection registers the v1 and v1alpha server reflection services
+	Reflection bool
+	// Channelz registers the grpc admin services, channelz being the only one without xds
+	Channelz bool
+	// DrainTimeout is how long a shutdown waits for in-flight rpcs before cutting them
+	DrainTimeout time.Duration
+	// Runtime is the initial traffic shape, changed later through the admin api
+	Runtime runtimeConfig
+	// Compression is the response compression of every call
+	Compression compressionConfig
+	// Orca sends ORCA load reports, per call and out of band, for weighted round robin clients
+	Orca bool
+	// Replicas is the number of StreamingServer replicas, on consecutive ports or on SocketDir
+	Replicas int
+	// SocketDir serves the replicas on unix sockets in this dir instead of tcp ports
+	SocketDir string
+	// ReplicaName is the identity of the server, replica i is <name>-<i> when there are several
+	ReplicaName string
+	// ReplicaRuntime is the initial runtime config of the first replicas, the others use Runtime
+	ReplicaRuntime []runtimeConfig
+	// MaxPayloadSize caps the payload size asked by a request or the runtime config
+	MaxPayloadSize int
+	// Upstream turns the server into a relay forwarding every StreamingService rpc to these servers
+	Upstream string
+}
+
+func server_start(cfg serverConfig, upstream *grpc.ClientConn, opts ...grpc.ServerOption) {
+	c, err := newCluster(cfg, upstream, opts)
 	if err != nil {
-		fmt.Printf("failed to listen: %v", err)
+		fmt.Println(err)
+		return
 	}
-	s := grpc.NewServer()
-	pb.RegisterStreamingServiceServer(s, &StreamingServer{})
-
-	// server mux for handle http&grpc req
-	// mux := runtime.NewServeMux()
-	// mux.HandlePath("GET", "/hello", runtime.HandlerFunc(func(w http.ResponseWriter, r *http.Request, pathParams map[string]string) {
-	// 	fmt.Println("http request touched")
-	// 	w.Write([]byte("hello, http"))
-	// }))
-
-	// go func() {
-	// 	if err := http.Serve(l, mux); err != nil {
-	// 		fmt.Printf("failed to serve http: %v", err)
-	// 	}
-	// }()
-
-	if err := s.Serve(l); err != nil {
-		fmt.Printf("failed to serve grpc: %v", err)
+	if err := c.start(); err != nil {
+		fmt.Println(err)
+		return
+	}
+	admin := newAdminServer(c)
+	if cfg.AdminAddr != "" {
+		go admin.serve(cfg.AdminAddr)
 	}
+
+	sig := make(chan os.Signal, 1)
+	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
+	received := <-sig
+	fmt.Printf("received %v, shutting down\n", received)
+	c.stop(true)
 }
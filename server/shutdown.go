@@ -0,0 +1,49 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+)
+
+// gracefulShutdown marks the server NOT_SERVING, sends GOAWAY and waits for the in-flight rpcs
+// up to timeout, then cuts the remaining ones.
+func gracefulShutdown(s *grpc.Server, healthServer *health.Server, registry *streamRegistry, httpServer *http.Server, timeout time.Duration) {
+	healthServer.Shutdown()
+
+	inflight := registry.startDrain()
+	fmt.Printf("draining %d in-flight rpcs, timeout %v\n", len(inflight), timeout)
+	for _, rpc := range inflight {
+		fmt.Printf("in-flight: %s\n", rpc)
+	}
+
+	// GracefulStop sends GOAWAY on every connection, stops accepting new rpcs and waits for the others
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+		fmt.Println("all rpcs finished, server stopped gracefully")
+	case <-time.After(timeout):
+		for _, rpc := range registry.forceClose() {
+			fmt.Printf("force-closed: %s\n", rpc)
+		}
+		s.Stop()
+		<-stopped
+		fmt.Println("server stopped after drain timeout")
+	}
+
+	if httpServer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		if err := httpServer.Shutdown(ctx); err != nil {
+			httpServer.Close()
+		}
+	}
+}
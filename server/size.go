@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// byteSize is a size flag accepting a K, M or G suffix, all powers of 1024, e.g. 512, 64K, 16MiB
+type byteSize int
+
+func (s *byteSize) String() string {
+	return strconv.Itoa(int(*s))
+}
+
+func (s *byteSize) Set(value string) error {
+	v := strings.TrimSuffix(strings.TrimSuffix(strings.ToUpper(strings.TrimSpace(value)), "B"), "I")
+	unit := 1
+	switch {
+	case strings.HasSuffix(v, "K"):
+		unit = 1 << 10
+	case strings.HasSuffix(v, "M"):
+		unit = 1 << 20
+	case strings.HasSuffix(v, "G"):
+		unit = 1 << 30
+	}
+	if unit > 1 {
+		v = v[:len(v)-1]
+	}
+	n, err := strconv.ParseFloat(v, 64)
+	// ParseFloat also reads NaN, Inf and exponents, the size has to fit an int64
+	if err != nil || math.IsNaN(n) || n < 0 || n*float64(unit) >= math.MaxInt64 {
+		return fmt.Errorf("invalid size %q", value)
+	}
+	*s = byteSize(n * float64(unit))
+	return nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"flag"
+	"io"
+	"testing"
+)
+
+// parseSizeFlags parses args the way main parses -max-recv-size and -max-send-size
+func parseSizeFlags(args ...string) (maxRecvSize, maxSendSize byteSize, err error) {
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	fs.Var(&maxRecvSize, "max-recv-size", "")
+	fs.Var(&maxSendSize, "max-send-size", "")
+	err = fs.Parse(args)
+	return maxRecvSize, maxSendSize, err
+}
+
+func TestMaxSizeFlags(t *testing.T) {
+	tests := []struct {
+		args     []string
+		wantRecv byteSize
+		wantSend byteSize
+	}{
+		{nil, 0, 0},
+		{[]string{"-max-recv-size=64M"}, 64 << 20, 0},
+		{[]string{"-max-recv-size", "4MiB", "-max-send-size", "512k"}, 4 << 20, 512 << 10},
+		{[]string{"-max-send-size=1.5G"}, 0, 3 << 29},
+		{[]string{"-max-recv-size=1048576"}, 1 << 20, 0},
+	}
+	for _, tt := range tests {
+		recv, send, err := parseSizeFlags(tt.args...)
+		if err != nil {
+			t.Errorf("%v: %v", tt.args, err)
+			continue
+		}
+		if recv != tt.wantRecv || send != tt.wantSend {
+			t.Errorf("%v: recv %d, send %d, want %d and %d", tt.args, recv, send, tt.wantRecv, tt.wantSend)
+		}
+	}
+}
+
+func TestMaxSizeFlagsInvalid(t *testing.T) {
+	for _, value := range []string{"", "-4M", "4X", "M", "NaN", "Inf", "1e30", "9000000000G"} {
+		if _, _, err := parseSizeFlags("-max-recv-size=" + value); err == nil {
+			t.Errorf("-max-recv-size=%s parsed, want an error", value)
+		}
+	}
+}
@@ -0,0 +1,315 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// inflightRPC is one rpc being handled by the server
+type inflightRPC struct {
+	id       uint64
+	method   string
+	peer     string
+	start    time.Time
+	metadata metadata.MD
+	attempt  int
+	msgsIn   atomic.Int64
+	msgsOut  atomic.Int64
+
+	// cancel cancels the context seen by the handler, with the kill status as its cause
+	cancel context.CancelCauseFunc
+	// killed is closed by kill, the stream calls of the handler then fail with killErr
+	killed   chan struct{}
+	killOnce sync.Once
+	killErr  error
+}
+
+func (r *inflightRPC) String() string {
+	return fmt.Sprintf("#%d %s from %s, running for %v", r.id, r.method, r.peer, time.Since(r.start).Round(time.Millisecond))
+}
+
+// kill ends the rpc with st, the other streams of the same connection go on
+func (r *inflightRPC) kill(st *status.Status) {
+	r.killOnce.Do(func() {
+		r.killErr = st.Err()
+		close(r.killed)
+		r.cancel(r.killErr)
+	})
+}
+
+// errClosed is the error of the stream calls made once the rpc was killed or ended
+func (r *inflightRPC) errClosed() error {
+	if r.killErr != nil {
+		return r.killErr
+	}
+	return status.Error(codes.Canceled, "stream is closed")
+}
+
+// isKilled reports whether kill was called, killErr is set once it returns true
+func (r *inflightRPC) isKilled() bool {
+	select {
+	case <-r.killed:
+		return true
+	default:
+		return false
+	}
+}
+
+// rpcInfo is the admin api view of an inflightRPC
+type rpcInfo struct {
+	ID       uint64      `json:"id"`
+	Method   string      `json:"method"`
+	Peer     string      `json:"peer"`
+	Start    time.Time   `json:"start"`
+	Duration string      `json:"duration"`
+	Metadata metadata.MD `json:"metadata"`
+	Attempt  int         `json:"attempt"`
+	MsgsIn   int64       `json:"msgs_in"`
+	MsgsOut  int64       `json:"msgs_out"`
+}
+
+func (r *inflightRPC) info() rpcInfo {
+	return rpcInfo{
+		ID:       r.id,
+		Method:   r.method,
+		Peer:     r.peer,
+		Start:    r.start,
+		Duration: time.Since(r.start).Round(time.Millisecond).String(),
+		Metadata: r.metadata,
+		Attempt:  r.attempt,
+		MsgsIn:   r.msgsIn.Load(),
+		MsgsOut:  r.msgsOut.Load(),
+	}
+}
+
+// streamRegistry keeps track of the in-flight rpcs, so they can be listed and killed through the
+// admin api, and a shutdown can tell which of them finished during the drain and which were force-closed.
+type streamRegistry struct {
+	mu       sync.Mutex
+	nextID   uint64
+	rpcs     map[uint64]*inflightRPC
+	draining bool
+	forced   bool
+}
+
+func newStreamRegistry() *streamRegistry {
+	return &streamRegistry{rpcs: map[uint64]*inflightRPC{}}
+}
+
+func (r *streamRegistry) add(ctx context.Context, method string, cancel context.CancelCauseFunc) *inflightRPC {
+	rpc := &inflightRPC{method: method, start: time.Now(), cancel: cancel, killed: make(chan struct{})}
+	if p, ok := peer.FromContext(ctx); ok {
+		rpc.peer = p.Addr.String()
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		rpc.metadata = md.Copy()
+	}
+	rpc.attempt = previousAttempts(rpc.metadata) + 1
+	r.mu.Lock()
+	r.nextID++
+	rpc.id = r.nextID
+	r.rpcs[rpc.id] = rpc
+	r.mu.Unlock()
+	if rpc.attempt > 1 {
+		fmt.Printf("#%d %s from %s is attempt %d of its call\n", rpc.id, rpc.method, rpc.peer, rpc.attempt)
+	}
+	return rpc
+}
+
+func (r *streamRegistry) remove(rpc *inflightRPC, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.rpcs, rpc.id)
+	if r.draining && !r.forced {
+		fmt.Printf("finished during drain: %s, code %v\n", rpc, status.Code(err))
+	}
+}
+
+// inflight returns the rpcs being handled, oldest first
+func (r *streamRegistry) inflight() []*inflightRPC {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	rpcs := make([]*inflightRPC, 0, len(r.rpcs))
+	for _, rpc := range r.rpcs {
+		rpcs = append(rpcs, rpc)
+	}
+	sort.Slice(rpcs, func(i, j int) bool { return rpcs[i].id < rpcs[j].id })
+	return rpcs
+}
+
+// kill ends the rpc id with st, it returns false when no such rpc is in flight
+func (r *streamRegistry) kill(id uint64, st *status.Status) bool {
+	r.mu.Lock()
+	rpc, ok := r.rpcs[id]
+	r.mu.Unlock()
+	if !ok {
+		return false
+	}
+	fmt.Printf("killing %s with %v\n", rpc, st.Err())
+	rpc.kill(st)
+	return true
+}
+
+func (r *streamRegistry) startDrain() []*inflightRPC {
+	r.mu.Lock()
+	r.draining = true
+	r.mu.Unlock()
+	return r.inflight()
+}
+
+// forceClose stops the drain logs and returns the rpcs left, which are about to be cut
+func (r *streamRegistry) forceClose() []*inflightRPC {
+	r.mu.Lock()
+	r.forced = true
+	r.mu.Unlock()
+	return r.inflight()
+}
+
+// The interceptors run the handler until it returns, a killed rpc only makes it return early: its context
+// is canceled and its later stream calls fail. The killed status replaces whatever the handler returned,
+// and grpc ends the stream once it did, which also ends a Send or Recv still pending on it.
+
+func (r *streamRegistry) unaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	rpc := r.add(ctx, info.FullMethod, cancel)
+	rpc.msgsIn.Add(1)
+	resp, err := handler(ctx, req)
+	if rpc.isKilled() {
+		resp, err = nil, rpc.killErr
+	} else if err == nil {
+		rpc.msgsOut.Add(1)
+	}
+	r.remove(rpc, err)
+	return resp, err
+}
+
+func (r *streamRegistry) streamInterceptor(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, cancel := context.WithCancelCause(ss.Context())
+	defer cancel(nil)
+	rpc := r.add(ctx, info.FullMethod, cancel)
+	err := handler(srv, &trackedStream{ServerStream: ss, ctx: ctx, rpc: rpc})
+	if rpc.isKilled() {
+		err = rpc.killErr
+	}
+	r.remove(rpc, err)
+	return err
+}
+
+// trackedStream counts the messages of a stream and fails every call once the rpc was killed
+type trackedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+	rpc *inflightRPC
+}
+
+func (s *trackedStream) Context() context.Context {
+	return s.ctx
+}
+
+// call runs a stream call unless the rpc was killed, a call failing meanwhile reports the kill
+func (s *trackedStream) call(fn func() error) error {
+	if s.rpc.isKilled() {
+		return s.rpc.errClosed()
+	}
+	if err := fn(); err != nil {
+		if s.rpc.isKilled() {
+			return s.rpc.errClosed()
+		}
+		return err
+	}
+	return nil
+}
+
+func (s *trackedStream) SendMsg(m any) error {
+	if err := s.call(func() error { return s.ServerStream.SendMsg(m) }); err != nil {
+		return err
+	}
+	s.rpc.msgsOut.Add(1)
+	return nil
+}
+
+func (s *trackedStream) SendHeader(md metadata.MD) error {
+	if s.rpc.isKilled() {
+		return s.rpc.errClosed()
+	}
+	return s.ServerStream.SendHeader(md)
+}
+
+func (s *trackedStream) SetHeader(md metadata.MD) error {
+	if s.rpc.isKilled() {
+		return s.rpc.errClosed()
+	}
+	return s.ServerStream.SetHeader(md)
+}
+
+func (s *trackedStream) SetTrailer(md metadata.MD) {
+	if !s.rpc.isKilled() {
+		s.ServerStream.SetTrailer(md)
+	}
+}
+
+func (s *trackedStream) RecvMsg(m any) error {
+	if err := s.call(func() error { return s.ServerStream.RecvMsg(m) }); err != nil {
+		return err
+	}
+	s.rpc.msgsIn.Add(1)
+	return nil
+}
+
+// received is a request read by recvLoop, or the error that ended the requests
+type received[T any] struct {
+	msg T
+	err error
+}
+
+// recvLoop reads the requests of a stream with recv in a goroutine of its own, so a handler can wait for
+// the next request and for the end of its rpc at once, e.g. killed while the client sends nothing.
+// The loop stops with the first error or once ctx is done, a Recv still pending then ends with the stream.
+func recvLoop[T any](ctx context.Context, recv func() (T, error)) <-chan received[T] {
+	requests := make(chan received[T])
+	go func() {
+		for {
+			msg, err := recv()
+			select {
+			case requests <- received[T]{msg: msg, err: err}:
+			case <-ctx.Done():
+				return
+			}
+			if err != nil {
+				return
+			}
+		}
+	}()
+	return requests
+}
+
+// nextRequest waits for the next request of recvLoop, it fails once ctx is done
+func nextRequest[T any](ctx context.Context, requests <-chan received[T]) (T, error) {
+	select {
+	case r := <-requests:
+		return r.msg, r.err
+	case <-ctx.Done():
+		var zero T
+		return zero, contextStatus(ctx)
+	}
+}
+
+// contextStatus is the status of a done rpc context: its kill status, or CANCELLED or DEADLINE_EXCEEDED
+func contextStatus(ctx context.Context) error {
+	cause := context.Cause(ctx)
+	if st, ok := status.FromError(cause); ok {
+		return st.Err()
+	}
+	return status.FromContextError(cause).Err()
+}
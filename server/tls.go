@@ -0,0 +1,192 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/credentials"
+)
+
+// tlsConfig is filled by the -tls-* flags
+type tlsConfig struct {
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+	// GenerateDir writes a throwaway CA, server and client certs there and serves with them
+	GenerateDir string
+	// Hosts are the extra SANs of the generated server cert
+	Hosts string
+}
+
+func (c *tlsConfig) enabled() bool {
+	return c.CertFile != "" || c.GenerateDir != ""
+}
+
+// validate rejects the flags that only make sense with a server cert, instead of serving plaintext
+func (c *tlsConfig) validate() error {
+	if c.enabled() {
+		return nil
+	}
+	if c.ClientCAFile != "" {
+		return fmt.Errorf("-tls-client-ca needs -tls-cert or -tls-gen-dir")
+	}
+	if c.KeyFile != "" {
+		return fmt.Errorf("-tls-key needs -tls-cert")
+	}
+	return nil
+}
+
+func (c *tlsConfig) serverCredentials() (credentials.TransportCredentials, error) {
+	cfg := &tls.Config{MinVersion: tls.VersionTLS12}
+	certFile, keyFile, clientCAFile := c.CertFile, c.KeyFile, c.ClientCAFile
+	if c.GenerateDir != "" {
+		if err := generateCerts(c.GenerateDir, strings.Split(c.Hosts, ",")); err != nil {
+			return nil, err
+		}
+		certFile = filepath.Join(c.GenerateDir, "server.pem")
+		keyFile = filepath.Join(c.GenerateDir, "server-key.pem")
+		// generated certs verify client certs when given, so both TLS and mTLS clients work
+		pool, err := loadCertPool(filepath.Join(c.GenerateDir, "ca.pem"))
+		if err != nil {
+			return nil, err
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.VerifyClientCertIfGiven
+		fmt.Printf("generated TLS certs in %s\n", c.GenerateDir)
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server cert: %w", err)
+	}
+	cfg.Certificates = []tls.Certificate{cert}
+	if clientCAFile != "" {
+		pool, err := loadCertPool(clientCAFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return credentials.NewTLS(cfg), nil
+}
+
+func loadCertPool(file string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificate found in %s", file)
+	}
+	return pool, nil
+}
+
+// peerIdentity describes the verified client certificate of a TLS peer
+func peerIdentity(authInfo credentials.AuthInfo) string {
+	info, ok := authInfo.(credentials.TLSInfo)
+	if !ok {
+		return ""
+	}
+	if len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return "tls, no client cert"
+	}
+	cert := info.State.VerifiedChains[0][0]
+	return fmt.Sprintf("subject=%s, issuer=%s, dns=%v, serial=%s", cert.Subject, cert.Issuer, cert.DNSNames, cert.SerialNumber.Text(16))
+}
+
+// generateCerts writes ca.pem, server.pem and client.pem with their -key.pem files into dir
+func generateCerts(dir string, hosts []string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return err
+	}
+	caTmpl := certTemplate("grpc-stream-demo CA")
+	caTmpl.IsCA = true
+	caTmpl.BasicConstraintsValid = true
+	caTmpl.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+	caDER, err := x509.CreateCertificate(rand.Reader, caTmpl, caTmpl, &caKey.PublicKey, caKey)
+	if err != nil {
+		return err
+	}
+	if err := writePEM(dir, "ca", caDER, caKey); err != nil {
+		return err
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		return err
+	}
+
+	serverTmpl := certTemplate("grpc-stream-demo server")
+	serverTmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	serverTmpl.DNSNames = []string{"localhost"}
+	serverTmpl.IPAddresses = []net.IP{net.IPv4(127, 0, 0, 1), net.IPv6loopback}
+	if hostname, err := os.Hostname(); err == nil {
+		serverTmpl.DNSNames = append(serverTmpl.DNSNames, hostname)
+	}
+	for _, h := range hosts {
+		h = strings.TrimSpace(h)
+		if ip := net.ParseIP(h); ip != nil {
+			serverTmpl.IPAddresses = append(serverTmpl.IPAddresses, ip)
+		} else if h != "" {
+			serverTmpl.DNSNames = append(serverTmpl.DNSNames, h)
+		}
+	}
+	clientTmpl := certTemplate("grpc-stream-demo client")
+	clientTmpl.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	clientTmpl.DNSNames = []string{"grpc-stream-demo-client"}
+
+	for name, tmpl := range map[string]*x509.Certificate{"server": serverTmpl, "client": clientTmpl} {
+		key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		if err != nil {
+			return err
+		}
+		der, err := x509.CreateCertificate(rand.Reader, tmpl, ca, &key.PublicKey, caKey)
+		if err != nil {
+			return err
+		}
+		if err := writePEM(dir, name, der, key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func certTemplate(cn string) *x509.Certificate {
+	serial, _ := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 64))
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: cn, Organization: []string{"grpc-stream-demo"}},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(30 * 24 * time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+	}
+}
+
+func writePEM(dir, name string, der []byte, key *ecdsa.PrivateKey) error {
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return err
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err := os.WriteFile(filepath.Join(dir, name+".pem"), certPEM, 0o644); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(dir, name+"-key.pem"), keyPEM, 0o600)
+}
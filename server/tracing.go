@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/propagators/b3"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"google.golang.org/grpc/stats"
+)
+
+// tracingConfig is filled by the -trace-* flags
+type tracingConfig struct {
+	// Exporter is one of none, stdout, file or otlp
+	Exporter     string
+	File         string
+	OTLPEndpoint string
+	// MessageEvents records every stream message as a span event
+	MessageEvents bool
+}
+
+// tracePropagator reads and writes both W3C traceparent/tracestate/baggage and B3 headers
+func tracePropagator() propagation.TextMapPropagator {
+	return propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+		b3.New(b3.WithInjectEncoding(b3.B3MultipleHeader|b3.B3SingleHeader)),
+	)
+}
+
+// setup builds the tracer provider, it returns nil handlers when tracing is disabled. The client handler
+// traces the upstream calls of relay mode. shutdown flushes the pending spans and must be called before exiting.
+func (c *tracingConfig) setup(serviceName string) (server, client stats.Handler, shutdown func(), err error) {
+	var spanProcessor sdktrace.SpanProcessor
+	switch c.Exporter {
+	case "", "none":
+		return nil, nil, func() {}, nil
+	case "stdout", "file":
+		var w io.Writer = os.Stdout
+		if c.Exporter == "file" {
+			f, err := os.OpenFile(c.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			w = f
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(w))
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		// write every span at once, so nothing is lost when the process is killed
+		spanProcessor = sdktrace.NewSimpleSpanProcessor(exporter)
+	case "otlp":
+		exporter, err := otlptracegrpc.New(context.Background(), otlptracegrpc.WithEndpoint(c.OTLPEndpoint), otlptracegrpc.WithInsecure())
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		spanProcessor = sdktrace.NewBatchSpanProcessor(exporter)
+	default:
+		return nil, nil, nil, fmt.Errorf("unknown trace exporter: %s", c.Exporter)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithSpanProcessor(spanProcessor),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+	)
+	opts := []otelgrpc.Option{
+		otelgrpc.WithTracerProvider(tp),
+		otelgrpc.WithPropagators(tracePropagator()),
+	}
+	if c.MessageEvents {
+		opts = append(opts, otelgrpc.WithMessageEvents(otelgrpc.ReceivedEvents, otelgrpc.SentEvents))
+	}
+	shutdown = func() {
+		if err := tp.Shutdown(context.Background()); err != nil {
+			fmt.Printf("failed to shutdown tracer provider: %v\n", err)
+		}
+	}
+	return otelgrpc.NewServerHandler(opts...), otelgrpc.NewClientHandler(opts...), shutdown, nil
+}
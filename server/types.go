@@ -0,0 +1,102 @@
+package main
+
+import (
+	"context"
+	"io"
+	"math"
+
+	"server/message/pb"
+
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// TypesServer echoes AllTypes messages, see types.proto
+type TypesServer struct {
+	pb.UnimplementedTypesServiceServer
+}
+
+// appendUnknownFields adds fields missing from the schema to m, one of every wire type,
+// so decoders also meet fields they have no descriptor for. The unknown fields m already has,
+// e.g. sent by the client, are kept.
+func appendUnknownFields(m proto.Message) {
+	var b []byte
+	b = protowire.AppendTag(b, 1000, protowire.VarintType)
+	b = protowire.AppendVarint(b, math.MaxUint64)
+	b = protowire.AppendTag(b, 1001, protowire.Fixed64Type)
+	b = protowire.AppendFixed64(b, math.Float64bits(math.Pi))
+	b = protowire.AppendTag(b, 1002, protowire.BytesType)
+	b = protowire.AppendString(b, "unknown length-delimited field")
+	b = protowire.AppendTag(b, 1003, protowire.Fixed32Type)
+	b = protowire.AppendFixed32(b, math.Float32bits(math.E))
+	// deprecated groups are still valid on the wire
+	b = protowire.AppendTag(b, 1004, protowire.StartGroupType)
+	b = protowire.AppendTag(b, 1, protowire.VarintType)
+	b = protowire.AppendVarint(b, 42)
+	b = protowire.AppendTag(b, 1004, protowire.EndGroupType)
+
+	r := m.ProtoReflect()
+	r.SetUnknown(append(r.GetUnknown(), b...))
+}
+
+func (s *TypesServer) Echo(ctx context.Context, req *pb.AllTypes) (*pb.AllTypes, error) {
+	resp := proto.Clone(req).(*pb.AllTypes)
+	appendUnknownFields(resp)
+	return resp, nil
+}
+
+func (s *TypesServer) EchoServerStream(req *pb.TypesStreamRequest, stream pb.TypesService_EchoServerStreamServer) error {
+	count := int(req.GetCount())
+	if count <= 0 {
+		count = 3
+	}
+	for i := 0; i < count; i++ {
+		resp := proto.Clone(req.GetTemplate()).(*pb.AllTypes)
+		if resp == nil {
+			resp = &pb.AllTypes{}
+		}
+		// make every message different, so decoders can't get away with caching
+		resp.Choice = &pb.AllTypes_ChoiceInt64{ChoiceInt64: int64(i)}
+		resp.Timestamp = timestamppb.Now()
+		appendUnknownFields(resp)
+		if err := stream.Send(resp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *TypesServer) EchoClientStream(stream pb.TypesService_EchoClientStreamServer) error {
+	summary := &pb.TypesSummary{}
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			if summary.Last != nil {
+				appendUnknownFields(summary.Last)
+			}
+			return stream.SendAndClose(summary)
+		}
+		if err != nil {
+			return err
+		}
+		summary.Count++
+		summary.Last = req
+	}
+}
+
+func (s *TypesServer) EchoBidiStream(stream pb.TypesService_EchoBidiStreamServer) error {
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		appendUnknownFields(req)
+		if err := stream.Send(req); err != nil {
+			return err
+		}
+	}
+}